@@ -62,6 +62,11 @@ func runAdd() {
 	new := kube.LoadConfigFromFile(addFile)
 	kube.StandardizeConfig(new)
 
+	// 添加前检查已废弃的认证配置，只提示不修改，避免脚本中添加时等待确认
+	if found, _ := migrateContexts(new, contextNames(new), false, true); found {
+		output.Note(`Run "ktx migrate" after adding to migrate the deprecated auth configuration.`)
+	}
+
 	merge(config, new)
 }
 
@@ -0,0 +1,260 @@
+/*
+Copyright © 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/ketches/ktx/internal/completion"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/output"
+	"github.com/ketches/ktx/internal/util"
+	"github.com/spf13/cobra"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+type canIFlags struct {
+	namespace     string
+	allNamespaces bool
+	contexts      []string
+	selector      string
+	all           bool
+	list          bool
+	timeout       time.Duration
+	parallel      int
+}
+
+var canIFlag canIFlags
+
+// canICmd represents the can-i command
+var canICmd = &cobra.Command{
+	Use:   "can-i <verbs> <resources>",
+	Short: "Check permissions across contexts",
+	Long: `Check permissions across contexts with SelfSubjectAccessReview, the current context by default.
+
+Verbs and resources are comma separated, every verb is checked on every
+resource. Resources are in the form of resource[.group][/subresource], or a
+non-resource URL starting with /. Each context is checked in its own namespace
+unless --namespace or --all-namespaces is specified.
+
+With --list the actions allowed in the namespace are listed with
+SelfSubjectRulesReview instead.
+
+Exits with code 1 if any action is denied or any check fails.`,
+	Example: `  ktx can-i create,delete deployments.apps -n prod --all
+  ktx can-i get pods/log --contexts dev,staging
+  ktx can-i --list -n kube-system`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if canIFlag.list {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		runCanI(args)
+	},
+	ValidArgsFunction: completion.None,
+}
+
+func init() {
+	rootCmd.AddCommand(canICmd)
+
+	canICmd.Flags().StringVarP(&canIFlag.namespace, "namespace", "n", "", "Namespace to check in, the namespace of each context by default")
+	canICmd.Flags().BoolVarP(&canIFlag.allNamespaces, "all-namespaces", "A", false, "Check in all namespaces")
+	canICmd.Flags().StringSliceVar(&canIFlag.contexts, "contexts", nil, "Contexts to check, comma separated")
+	canICmd.Flags().StringVarP(&canIFlag.selector, "selector", "l", "", "Check contexts matching the label selector, eg. env=prod")
+	canICmd.Flags().BoolVar(&canIFlag.all, "all", false, "Check all contexts")
+	canICmd.Flags().BoolVar(&canIFlag.list, "list", false, "List the actions allowed in the namespace")
+	canICmd.Flags().DurationVar(&canIFlag.timeout, "timeout", 10*time.Second, "Timeout of checking each context")
+	canICmd.Flags().IntVar(&canIFlag.parallel, "parallel", 16, "Maximum number of contexts checked concurrently")
+
+	canICmd.RegisterFlagCompletionFunc("contexts", completion.ContextArray)
+}
+
+// canIResult is the result of checking a context.
+type canIResult struct {
+	context   string
+	namespace string
+	access    []kube.AccessResult
+	rules     *authorizationv1.SubjectRulesReviewStatus
+	err       error
+}
+
+func runCanI(args []string) {
+	config := kube.LoadConfigFromFile(rootFlag.kubeconfig)
+	if canIFlag.list && canIFlag.allNamespaces {
+		output.Fatal("--list does not support --all-namespaces.")
+	}
+
+	var checks []kube.AccessCheck
+	if !canIFlag.list {
+		for _, verb := range strings.Split(args[0], ",") {
+			for _, resource := range strings.Split(args[1], ",") {
+				checks = append(checks, kube.ParseAccessCheck(verb, resource))
+			}
+		}
+	}
+
+	dsts := selectContexts(config, canIFlag.all, canIFlag.contexts, canIFlag.selector)
+	results := make([]*canIResult, len(dsts))
+	for i, dst := range dsts {
+		namespace := canIFlag.namespace
+		if len(namespace) == 0 && !canIFlag.allNamespaces {
+			namespace = util.If(len(config.Contexts[dst].Namespace) > 0, config.Contexts[dst].Namespace, kube.DefaultNamespace)
+		}
+		results[i] = &canIResult{context: dst, namespace: namespace}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	util.Parallel(ctx, results, canIFlag.parallel, func(ctx context.Context, result *canIResult) {
+		if canIFlag.list {
+			result.rules, result.err = kube.Rules(ctx, rootFlag.kubeconfig, result.context, result.namespace, canIFlag.timeout)
+		} else {
+			result.access, result.err = kube.CanI(ctx, rootFlag.kubeconfig, result.context, result.namespace, checks, canIFlag.timeout)
+		}
+	})
+
+	var failed bool
+	for _, result := range results {
+		if result.access == nil && result.rules == nil && result.err == nil {
+			result.err = context.Canceled
+		}
+		if result.err != nil {
+			failed = true
+		}
+		for _, access := range result.access {
+			failed = failed || !access.Allowed
+		}
+	}
+
+	if canIFlag.list {
+		printRules(results)
+	} else {
+		printAccessMatrix(checks, results)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func printAccessMatrix(checks []kube.AccessCheck, results []*canIResult) {
+	t := table.NewWriter()
+	row := table.Row{"context", "namespace"}
+	for _, check := range checks {
+		row = append(row, check.String())
+	}
+	t.AppendHeader(row)
+
+	for _, result := range results {
+		row := table.Row{result.context, util.If(result.namespace == "", "*", result.namespace)}
+		for i := range checks {
+			switch {
+			case result.err != nil:
+				row = append(row, kube.ClassifyError(result.err).ColorString())
+			case result.access[i].Allowed:
+				row = append(row, color.GreenString("yes"))
+			default:
+				row = append(row, color.RedString("no"))
+			}
+		}
+		t.AppendRow(row)
+	}
+	t.SetStyle(tableStyle)
+	fmt.Println(t.Render())
+}
+
+func printRules(results []*canIResult) {
+	for i, result := range results {
+		if i > 0 {
+			fmt.Println()
+		}
+		output.Note("Context <%s>, namespace <%s>:", result.context, result.namespace)
+		if result.err != nil {
+			output.Fail("Failed to list actions: %s", result.err)
+			continue
+		}
+
+		t := table.NewWriter()
+		t.AppendHeader(table.Row{"resources", "non-resource urls", "resource names", "verbs"})
+		for _, rule := range result.rules.ResourceRules {
+			var resources []string
+			for _, resource := range rule.Resources {
+				for _, group := range rule.APIGroups {
+					resources = append(resources, resource+util.If(group == "", "", "."+group))
+				}
+			}
+			t.AppendRow(table.Row{strings.Join(resources, ", "), "[]", fmt.Sprint(rule.ResourceNames), fmt.Sprint(rule.Verbs)})
+		}
+		for _, rule := range result.rules.NonResourceRules {
+			t.AppendRow(table.Row{"", fmt.Sprint(rule.NonResourceURLs), "[]", fmt.Sprint(rule.Verbs)})
+		}
+		t.SetStyle(tableStyle)
+		fmt.Println(t.Render())
+
+		if result.rules.Incomplete {
+			output.Note("The list may be incomplete: %s", result.rules.EvaluationError)
+		}
+	}
+}
+
+// selectContexts returns all contexts, the contexts matching the label
+// selector, the named contexts or the current context, which is the context
+// of the shell session if in one, and exits if any is not found.
+func selectContexts(config *clientcmdapi.Config, all bool, names []string, selector string) []string {
+	var dsts []string
+	switch {
+	case all:
+		dsts = contextNames(config)
+	case len(selector) > 0:
+		sel, err := labels.Parse(selector)
+		if err != nil {
+			output.Fatal("Invalid selector <%s>: %s", selector, err)
+		}
+		dsts = loadState().Select(contextNames(config), sel)
+		if len(dsts) == 0 {
+			output.Fatal("No context matches selector <%s>.", selector)
+		}
+	case len(names) > 0:
+		dsts = names
+	default:
+		// 在 ktx shell 会话中使用会话的 context，与 shell 中的 kubectl 一致
+		current, _ := currentContext(config)
+		if len(current) == 0 {
+			output.Fatal("No current context, specify contexts or --all.")
+		}
+		dsts = []string{current}
+	}
+
+	for _, dst := range dsts {
+		if _, ok := config.Contexts[dst]; !ok {
+			output.Fatal("Context <%s> not found.", dst)
+		}
+	}
+	return dsts
+}
@@ -0,0 +1,184 @@
+/*
+Copyright © 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/ketches/ktx/internal/completion"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/output"
+	"github.com/ketches/ktx/internal/util"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+type compareFlags struct {
+	output  string
+	timeout time.Duration
+}
+
+var compareFlag compareFlags
+
+// compareCmd represents the compare command
+var compareCmd = &cobra.Command{
+	Use:   "compare <context-a> <context-b>",
+	Short: "Compare the API surfaces of the clusters of two contexts",
+	Long: `Compare the server versions, API group versions, resources and served CRD
+versions of the clusters of two contexts, eg. before promoting workloads from
+staging to prod.
+
+Lines starting with "-" are only served by context A, "+" only by context B.
+Resources are compared in the group versions served by both. Group versions
+failing discovery on either side, eg. metrics.k8s.io when metrics-server is
+down, are skipped with a warning. CRDs are not compared if either context can
+not list them. Exits with code 1 if the API surfaces differ, a different
+server version alone is not a difference.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		runCompare(args)
+	},
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) >= 2 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completion.ContextArray(cmd, args, toComplete)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+
+	compareCmd.Flags().StringVarP(&compareFlag.output, "output", "o", "", "Output format, one of: json, yaml")
+	compareCmd.Flags().DurationVar(&compareFlag.timeout, "timeout", 30*time.Second, "Timeout of discovering each context")
+}
+
+// compareSide is a context being compared.
+type compareSide struct {
+	Context  string   `json:"context"`
+	Version  string   `json:"version"`
+	Warnings []string `json:"warnings,omitempty"`
+	surface  *kube.APISurface
+	err      error
+}
+
+// compareResult is the difference between the API surfaces of two contexts.
+type compareResult struct {
+	A *compareSide `json:"a"`
+	B *compareSide `json:"b"`
+	*kube.SurfaceDiff
+}
+
+func runCompare(args []string) {
+	if compareFlag.output != "" && compareFlag.output != "json" && compareFlag.output != "yaml" {
+		output.Fatal("Unsupported output format <%s>.", compareFlag.output)
+	}
+
+	config := kube.LoadConfigFromFile(rootFlag.kubeconfig)
+	dsts := selectContexts(config, false, args, "")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	sides := []*compareSide{{Context: dsts[0], err: context.Canceled}, {Context: dsts[1], err: context.Canceled}}
+	util.Parallel(ctx, sides, 2, func(ctx context.Context, side *compareSide) {
+		side.surface, side.err = kube.DiscoverAPISurface(ctx, rootFlag.kubeconfig, side.Context, compareFlag.timeout)
+	})
+	for _, side := range sides {
+		if side.err != nil {
+			output.Fatal("Failed to discover the API of context <%s>: %s", side.Context, side.err)
+		}
+		side.Version = side.surface.Version
+		side.Warnings = side.surface.Warnings
+	}
+
+	result := &compareResult{A: sides[0], B: sides[1], SurfaceDiff: kube.CompareAPISurfaces(sides[0].surface, sides[1].surface)}
+
+	switch compareFlag.output {
+	case "json":
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			output.Fatal("Failed to marshal the differences: %s", err)
+		}
+		fmt.Println(string(data))
+	case "yaml":
+		data, err := yaml.Marshal(result)
+		if err != nil {
+			output.Fatal("Failed to marshal the differences: %s", err)
+		}
+		fmt.Print(string(data))
+	default:
+		printCompareResult(result)
+	}
+
+	if !result.Empty() {
+		os.Exit(1)
+	}
+}
+
+func printCompareResult(result *compareResult) {
+	for _, side := range []*compareSide{result.A, result.B} {
+		for _, warning := range side.Warnings {
+			output.Warn("Context <%s>: %s", side.Context, warning)
+		}
+	}
+
+	if result.Empty() {
+		output.Done("No API differences between context <%s> (%s) and <%s> (%s).",
+			result.A.Context, result.A.Version, result.B.Context, result.B.Version)
+		return
+	}
+
+	version := func(side *compareSide) string {
+		if result.A.Version == result.B.Version {
+			return side.Version
+		}
+		return color.YellowString(side.Version)
+	}
+	fmt.Println(color.RedString("--- %s", result.A.Context), version(result.A))
+	fmt.Println(color.GreenString("+++ %s", result.B.Context), version(result.B))
+
+	section := func(title string, diff kube.SetDiff) {
+		if diff.Empty() {
+			return
+		}
+		fmt.Printf("\n%s:\n", title)
+		for _, item := range diff.OnlyA {
+			fmt.Println(color.RedString("- %s", item))
+		}
+		for _, item := range diff.OnlyB {
+			fmt.Println(color.GreenString("+ %s", item))
+		}
+	}
+	section("API group versions", result.GroupVersions)
+	section("Resources", result.Resources)
+	section("CRDs", result.CRDs)
+
+	if len(result.CRDVersions) > 0 {
+		fmt.Println("\nCRD versions:")
+		for _, crd := range result.CRDVersions {
+			fmt.Println(color.YellowString("~ %s", crd.Name), strings.Join(crd.A, ", "), "→", strings.Join(crd.B, ", "))
+		}
+	}
+}
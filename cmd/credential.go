@@ -0,0 +1,113 @@
+/*
+Copyright © 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ketches/ktx/internal/completion"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/oidc"
+	"github.com/ketches/ktx/internal/output"
+	"github.com/ketches/ktx/internal/vault"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientauthv1 "k8s.io/client-go/pkg/apis/clientauthentication/v1"
+)
+
+// credentialCmd represents the credential command
+var credentialCmd = &cobra.Command{
+	Use:   "credential",
+	Short: "Print the vault credential of a context as an ExecCredential",
+	Long: `Print the vault credential of a context as an ExecCredential.
+
+This command implements the client.authentication.k8s.io/v1 exec credential
+plugin protocol, and is called by kubectl for contexts sealed by "ktx vault seal".`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runCredential(args[0])
+	},
+	ValidArgsFunction: completion.Context,
+}
+
+func init() {
+	rootCmd.AddCommand(credentialCmd)
+}
+
+func runCredential(key string) {
+	// stdout 仅输出 ExecCredential
+	output.UseStderr()
+
+	v := openVault()
+	entry, ok := v.Entries[key]
+	if !ok {
+		output.Fatal("Credential <%s> not found in vault.", key)
+	}
+
+	if entry.OIDC != nil {
+		printOIDCCredential(v, key, entry.OIDC)
+		return
+	}
+
+	kube.PrintExecCredential(&clientauthv1.ExecCredentialStatus{
+		Token:                 entry.Token,
+		ClientCertificateData: string(entry.ClientCertificateData),
+		ClientKeyData:         string(entry.ClientKeyData),
+	})
+}
+
+// printOIDCCredential prints the ID token of an OIDC login, the token is
+// refreshed and saved back to the vault when it is about to expire. A token
+// of unknown expiry is used until the server rejects it.
+func printOIDCCredential(v *vault.Vault, key string, login *vault.OIDC) {
+	if !login.Expiry.IsZero() && time.Now().Add(time.Minute).After(login.Expiry) {
+		if len(login.RefreshToken) == 0 {
+			output.Fatal("OIDC token of <%s> expired, run `ktx login %s` again.", key, key)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		token, err := oidcClient(login).Refresh(ctx, login.RefreshToken)
+		if errors.Is(err, oidc.ErrNoIDToken) {
+			// 保存轮换后的刷新令牌，原刷新令牌可能已失效
+			login.RefreshToken = token.RefreshToken
+			if err := v.Save(); err != nil {
+				output.Fail("Failed to save vault: %s", err)
+			}
+			output.Fatal("OIDC issuer returned no ID token when refreshing <%s>, run `ktx login %s` again.", key, key)
+		}
+		if err != nil {
+			output.Fatal("Failed to refresh OIDC token of <%s>, run `ktx login %s` again: %s", key, key, err)
+		}
+		login.IDToken = token.IDToken
+		login.RefreshToken = token.RefreshToken
+		login.Expiry = token.Expiry
+
+		if err := v.Save(); err != nil {
+			output.Fatal("Failed to save vault: %s", err)
+		}
+	}
+
+	status := &clientauthv1.ExecCredentialStatus{Token: login.IDToken}
+	if !login.Expiry.IsZero() {
+		status.ExpirationTimestamp = &metav1.Time{Time: login.Expiry}
+	}
+	kube.PrintExecCredential(status)
+}
@@ -0,0 +1,138 @@
+/*
+Copyright © 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/ketches/ktx/internal/completion"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/output"
+	"github.com/ketches/ktx/internal/prompt"
+	"github.com/ketches/ktx/internal/util"
+	"github.com/spf13/cobra"
+)
+
+type doctorFlags struct {
+	fix bool
+	yes bool
+}
+
+var doctorFlag doctorFlags
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Validate and repair the kubeconfig",
+	Long: `Validate and repair the kubeconfig.
+
+Reports dangling contexts, orphaned clusters and users, unreadable or invalid
+certificates, expired client certificates, missing exec plugins, duplicate
+servers and unsafe file permissions. With --fix the safe repairs are applied:
+dropping dangling contexts, pruning orphans and chmod 0600.
+
+Exits with a non-zero code if errors remain.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runDoctor()
+	},
+	ValidArgsFunction: completion.None,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().BoolVar(&doctorFlag.fix, "fix", false, "Apply safe repairs")
+	doctorCmd.Flags().BoolVarP(&doctorFlag.yes, "yes", "y", false, "Apply repairs without confirmation")
+}
+
+func runDoctor() {
+	config := kube.LoadConfigFromFile(rootFlag.kubeconfig)
+
+	issues := kube.Diagnose(config, rootFlag.kubeconfig)
+	if len(issues) == 0 {
+		output.Done("No issues found.")
+		return
+	}
+	printIssues(issues)
+
+	if doctorFlag.fix {
+		// 修复后可能产生新的孤立 cluster/user，重新检查直到没有可修复项
+		asked := map[kube.Issue]bool{}
+		for {
+			repaired, changed := false, false
+			for _, issue := range issues {
+				key := kube.Issue{Kind: issue.Kind, Name: issue.Name, Repair: issue.Repair}
+				if issue.Repair == kube.RepairNone || asked[key] {
+					continue
+				}
+				asked[key] = true
+
+				if !doctorFlag.yes && !prompt.YesNo(fmt.Sprintf("Apply %s to %s %s (%s)", issue.Repair, issue.Kind, issue.Name, issue.Message)) {
+					continue
+				}
+				if err := kube.ApplyRepair(config, rootFlag.kubeconfig, issue); err != nil {
+					output.Fail("Failed to %s %s <%s>: %s", issue.Repair, issue.Kind, issue.Name, err)
+					continue
+				}
+				repaired = true
+				changed = changed || issue.Repair != kube.RepairChmod
+				output.Done("Applied %s to %s <%s>.", issue.Repair, issue.Kind, issue.Name)
+			}
+			if changed {
+				kube.SaveConfigToFile(config, rootFlag.kubeconfig)
+			}
+			if !repaired {
+				break
+			}
+			issues = kube.Diagnose(config, rootFlag.kubeconfig)
+		}
+	}
+
+	var errs int
+	for _, issue := range issues {
+		if issue.Severity == kube.SeverityError {
+			errs++
+		}
+	}
+	if errs > 0 {
+		output.Fail("%d error(s) found.", errs)
+		os.Exit(1)
+	}
+}
+
+func printIssues(issues []*kube.Issue) {
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"severity", "kind", "name", "issue", "fix"})
+	for _, issue := range issues {
+		severity := issue.Severity.String()
+		switch issue.Severity {
+		case kube.SeverityError:
+			severity = color.RedString(severity)
+		case kube.SeverityWarning:
+			severity = color.YellowString(severity)
+		default:
+			severity = color.New(color.Faint).Sprint(severity)
+		}
+		t.AppendRow(table.Row{severity, issue.Kind, issue.Name, issue.Message, util.If(issue.Repair == kube.RepairNone, "-", issue.Repair.String())})
+	}
+	t.SetStyle(tableStyle)
+	fmt.Println(t.Render())
+}
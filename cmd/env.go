@@ -0,0 +1,107 @@
+/*
+Copyright © 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ketches/ktx/internal/completion"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/output"
+	"github.com/ketches/ktx/internal/session"
+	"github.com/spf13/cobra"
+)
+
+type envFlags struct {
+	namespace string
+	shell     string
+	unset     bool
+}
+
+var envFlag envFlags
+
+// envCmd represents the env command
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Print shell commands isolating the current shell to a context",
+	Long: `Print shell commands pointing KUBECONFIG at a session kubeconfig holding only the context.
+
+The current context is used if not specified. Evaluate the output in the
+shell, eg. eval "$(ktx env prod)" for bash and zsh, ktx env prod | source for
+fish, or ktx env prod | Invoke-Expression for PowerShell. The session
+kubeconfig is removed when the shell exits, running it again in the same shell
+reuses the session.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runEnv(args)
+	},
+	ValidArgsFunction: completion.Context,
+}
+
+func init() {
+	rootCmd.AddCommand(envCmd)
+
+	envCmd.Flags().StringVarP(&envFlag.namespace, "namespace", "n", "", "Namespace of the session, the namespace of the context by default")
+	envCmd.Flags().StringVar(&envFlag.shell, "shell", session.DetectShell(), "Shell to print commands for, one of: bash, zsh, fish, powershell")
+	envCmd.Flags().BoolVarP(&envFlag.unset, "unset", "u", false, "Print commands leaving the session")
+
+	envCmd.RegisterFlagCompletionFunc("shell", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return session.Shells, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+func runEnv(args []string) {
+	// 标准输出会被 shell 执行，消息输出到标准错误
+	output.UseStderr()
+	if !slices.Contains(session.Shells, envFlag.shell) {
+		output.Fatal("Unsupported shell <%s>, supported: %s.", envFlag.shell, strings.Join(session.Shells, ", "))
+	}
+
+	if envFlag.unset {
+		script, _ := session.UnsetScript(envFlag.shell)
+		if file := session.Current(); len(file) > 0 {
+			os.Remove(file)
+		}
+		fmt.Print(script)
+		return
+	}
+
+	config := kube.LoadConfigFromFile(rootFlag.kubeconfig)
+
+	// 同一个 shell 中再次执行时复用会话文件，清理钩子已注册
+	file, cleanup := session.Current(), false
+	switch {
+	case len(args) > 0:
+		if len(file) > 0 {
+			writeSession(file, config, args[0], envFlag.namespace)
+		} else {
+			file, cleanup = newSession(config, args[0], envFlag.namespace), true
+		}
+	case len(file) > 0:
+		// 已在会话中，保持会话的 context
+	case len(config.CurrentContext) > 0:
+		file, cleanup = newSession(config, config.CurrentContext, envFlag.namespace), true
+	default:
+		output.Fatal("No current context, specify a context.")
+	}
+
+	script, _ := session.Script(envFlag.shell, file, cleanup)
+	fmt.Print(script)
+}
@@ -0,0 +1,259 @@
+/*
+Copyright © 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/ketches/ktx/internal/completion"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/output"
+	"github.com/ketches/ktx/internal/util"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+type execFlags struct {
+	contexts  []string
+	selector  string
+	all       bool
+	namespace string
+	parallel  int
+	timeout   time.Duration
+	failFast  bool
+	group     bool
+}
+
+var execFlag execFlags
+
+// execCmd represents the exec command
+var execCmd = &cobra.Command{
+	Use:   "exec [flags] -- <command> [args...]",
+	Short: "Run a command across contexts",
+	Long: `Run a command once per context, the current context by default.
+
+Each run gets an isolated temporary KUBECONFIG holding only its context, so
+kubectl and other tools target the context without touching current-context.
+Output lines are prefixed with the context name, or grouped per context with
+--group, and the exit codes are summarized at the end.
+
+Exits with code 1 if the command fails in any context.`,
+	Example: `  ktx exec --all -- kubectl get nodes
+  ktx exec -l env=prod --parallel 4 --fail-fast -- kubectl rollout status deploy/api -n payments
+  ktx exec --contexts dev,staging --group -- helm list -A`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runExec(args)
+	},
+	ValidArgsFunction: completion.None,
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+
+	execCmd.Flags().StringSliceVar(&execFlag.contexts, "contexts", nil, "Contexts to run in, comma separated")
+	execCmd.Flags().StringVarP(&execFlag.selector, "selector", "l", "", "Run in contexts matching the label selector, eg. env=prod")
+	execCmd.Flags().BoolVar(&execFlag.all, "all", false, "Run in all contexts")
+	execCmd.Flags().StringVarP(&execFlag.namespace, "namespace", "n", "", "Namespace pinned in each context, the namespace of the context by default")
+	execCmd.Flags().IntVar(&execFlag.parallel, "parallel", 4, "Maximum number of contexts run concurrently")
+	execCmd.Flags().DurationVar(&execFlag.timeout, "timeout", 0, "Timeout of each run, 0 means no timeout")
+	execCmd.Flags().BoolVar(&execFlag.failFast, "fail-fast", false, "Stop all runs once a run fails")
+	execCmd.Flags().BoolVar(&execFlag.group, "group", false, "Print output grouped per context instead of prefixed lines")
+
+	execCmd.RegisterFlagCompletionFunc("contexts", completion.ContextArray)
+}
+
+type execStatus string
+
+const (
+	execStatusSucceeded execStatus = "✓ Succeeded"
+	execStatusFailed    execStatus = "✗ Failed"
+	execStatusTimeout   execStatus = "✗ Timeout"
+	execStatusCanceled  execStatus = "✗ Canceled"
+	execStatusSkipped   execStatus = "- Skipped"
+)
+
+// execResult is the result of running the command in a context.
+type execResult struct {
+	context    string
+	kubeconfig string
+	status     execStatus
+	exitCode   int
+	duration   time.Duration
+}
+
+func runExec(args []string) {
+	config := kube.LoadConfigFromFile(rootFlag.kubeconfig)
+	dsts := selectContexts(config, execFlag.all, execFlag.contexts, execFlag.selector)
+
+	results, err := execInContexts(config, dsts, args)
+	if err != nil {
+		output.Fatal("%s", err)
+	}
+
+	printExecSummary(results)
+	for _, result := range results {
+		if result.status != execStatusSucceeded {
+			os.Exit(1)
+		}
+	}
+}
+
+// execInContexts runs the command in the contexts. The temporary kubeconfigs
+// holding the credentials are removed when it returns, including when
+// interrupted or terminated.
+func execInContexts(config *clientcmdapi.Config, dsts []string, args []string) ([]*execResult, error) {
+	// 在创建临时目录前精简，MinifyConfig 出错时直接退出
+	configs := make([]*clientcmdapi.Config, len(dsts))
+	for i, dst := range dsts {
+		configs[i] = kube.MinifyConfig(config, dst, execFlag.namespace)
+	}
+
+	dir, err := os.MkdirTemp("", "ktx-exec-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// 信号只取消运行，临时文件由 defer 删除
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer stop()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		results = make([]*execResult, len(dsts))
+		width   int
+	)
+	for i, dst := range dsts {
+		file := filepath.Join(dir, strconv.Itoa(i))
+		if err := clientcmd.WriteToFile(*configs[i], file); err != nil {
+			return nil, fmt.Errorf("failed to write kubeconfig of context <%s>: %w", dst, err)
+		}
+		results[i] = &execResult{context: dst, kubeconfig: file, status: execStatusSkipped, exitCode: -1}
+		width = max(width, len(dst))
+	}
+
+	var mu sync.Mutex
+	util.Parallel(ctx, results, execFlag.parallel, func(ctx context.Context, result *execResult) {
+		if execFlag.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, execFlag.timeout)
+			defer cancel()
+		}
+
+		c := exec.CommandContext(ctx, args[0], args[1:]...)
+		c.Env = append(os.Environ(), "KUBECONFIG="+result.kubeconfig)
+		c.WaitDelay = time.Second
+
+		var (
+			buf            bytes.Buffer
+			stdout, stderr *util.PrefixWriter
+		)
+		if execFlag.group {
+			c.Stdout, c.Stderr = &buf, &buf
+		} else {
+			prefix := contextColor(result.context).Sprintf("%-*s │ ", width, result.context)
+			stdout = util.NewPrefixWriter(os.Stdout, prefix, &mu)
+			stderr = util.NewPrefixWriter(os.Stderr, prefix, &mu)
+			c.Stdout, c.Stderr = stdout, stderr
+		}
+
+		start := time.Now()
+		err := c.Run()
+		result.duration = time.Since(start)
+
+		if execFlag.group {
+			mu.Lock()
+			contextColor(result.context).Printf("==> %s <==\n", result.context)
+			os.Stdout.Write(buf.Bytes())
+			mu.Unlock()
+		} else {
+			stdout.Flush()
+			stderr.Flush()
+		}
+
+		// 被信号终止时 ExitCode 为 -1，视为超时或取消
+		var exitErr *exec.ExitError
+		switch {
+		case err == nil:
+			result.status, result.exitCode = execStatusSucceeded, 0
+		case errors.As(err, &exitErr) && exitErr.ExitCode() >= 0:
+			result.status, result.exitCode = execStatusFailed, exitErr.ExitCode()
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			result.status = execStatusTimeout
+		case ctx.Err() != nil:
+			result.status = execStatusCanceled
+		default:
+			result.status = execStatusFailed
+			output.Fail("Failed to run in context <%s>: %s", result.context, err)
+		}
+
+		if result.status != execStatusSucceeded && execFlag.failFast {
+			cancel()
+		}
+	})
+	return results, nil
+}
+
+func printExecSummary(results []*execResult) {
+	fmt.Println()
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"context", "status", "exit code", "duration"})
+	for _, result := range results {
+		status := string(result.status)
+		switch result.status {
+		case execStatusSucceeded:
+			status = color.GreenString(status)
+		case execStatusFailed, execStatusTimeout:
+			status = color.RedString(status)
+		}
+		t.AppendRow(table.Row{
+			result.context,
+			status,
+			util.If(result.exitCode < 0, "-", strconv.Itoa(result.exitCode)),
+			util.If(result.duration == 0, "-", result.duration.Round(time.Millisecond).String()),
+		})
+	}
+	t.SetStyle(tableStyle)
+	fmt.Println(t.Render())
+}
+
+var contextColors = []color.Attribute{color.FgCyan, color.FgMagenta, color.FgBlue, color.FgYellow, color.FgGreen}
+
+// contextColor returns a stable color of the context name.
+func contextColor(name string) *color.Color {
+	var h int
+	for _, r := range name {
+		h = h*31 + int(r)
+	}
+	return color.New(contextColors[(h%len(contextColors)+len(contextColors))%len(contextColors)])
+}
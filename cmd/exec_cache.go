@@ -0,0 +1,268 @@
+/*
+Copyright © 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ketches/ktx/internal/completion"
+	"github.com/ketches/ktx/internal/execcache"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/output"
+	"github.com/ketches/ktx/internal/prompt"
+	"github.com/spf13/cobra"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// execCacheCmd represents the exec-cache command
+var execCacheCmd = &cobra.Command{
+	Use:   "exec-cache",
+	Short: "Cache credentials of slow exec credential plugins",
+	Long: `Cache credentials of slow exec credential plugins.
+
+Wrapped contexts call ktx instead of the original exec plugin (eg. aws eks get-token,
+kubelogin), ktx invokes the original plugin and caches the returned credential,
+in files readable only by the current user, until its expirationTimestamp.`,
+	ValidArgsFunction: completion.None,
+}
+
+// execCacheWrapCmd represents the exec-cache wrap command
+var execCacheWrapCmd = &cobra.Command{
+	Use:   "wrap",
+	Short: "Wrap context exec plugin with the ktx credential cache",
+	Long:  `Wrap context exec plugin with the ktx credential cache`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runExecCacheWrap(args)
+	},
+	ValidArgsFunction: completion.ContextArray,
+}
+
+// execCacheUnwrapCmd represents the exec-cache unwrap command
+var execCacheUnwrapCmd = &cobra.Command{
+	Use:   "unwrap",
+	Short: "Restore the original context exec plugin",
+	Long:  `Restore the original context exec plugin, and drop its cached credential`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runExecCacheUnwrap(args)
+	},
+	ValidArgsFunction: completion.ContextArray,
+}
+
+// execCacheClearCmd represents the exec-cache clear command
+var execCacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Drop cached credentials of wrapped contexts",
+	Long:  `Drop cached credentials of wrapped contexts, all wrapped contexts by default`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runExecCacheClear(args)
+	},
+	ValidArgsFunction: completion.ContextArray,
+}
+
+// execCacheRunCmd represents the exec-cache run command
+var execCacheRunCmd = &cobra.Command{
+	Use:    "run",
+	Short:  "Print the cached credential of a wrapped context as an ExecCredential",
+	Long:   `Print the cached credential of a wrapped context as an ExecCredential, called by kubectl.`,
+	Args:   cobra.ExactArgs(1),
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		runExecCacheRun(args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(execCacheCmd)
+	execCacheCmd.AddCommand(execCacheWrapCmd)
+	execCacheCmd.AddCommand(execCacheUnwrapCmd)
+	execCacheCmd.AddCommand(execCacheClearCmd)
+	execCacheCmd.AddCommand(execCacheRunCmd)
+}
+
+func runExecCacheWrap(args []string) {
+	config := kube.LoadConfigFromFile(rootFlag.kubeconfig)
+
+	dsts := args
+	if len(dsts) == 0 {
+		dsts = []string{prompt.ContextSelection("Select context to wrap", config)}
+	}
+
+	cache := execcache.New(execcache.DefaultDir)
+	for _, dst := range dsts {
+		wrapContextExec(config, cache, dst)
+	}
+}
+
+func runExecCacheUnwrap(args []string) {
+	config := kube.LoadConfigFromFile(rootFlag.kubeconfig)
+
+	dsts := args
+	if len(dsts) == 0 {
+		dsts = []string{prompt.ContextSelection("Select context to unwrap", config)}
+	}
+
+	cache := execcache.New(execcache.DefaultDir)
+	for _, dst := range dsts {
+		unwrapContextExec(config, cache, dst)
+	}
+}
+
+func runExecCacheClear(args []string) {
+	config := kube.LoadConfigFromFile(rootFlag.kubeconfig)
+	cache := execcache.New(execcache.DefaultDir)
+
+	dsts := args
+	if len(dsts) == 0 {
+		for ctxName, ctx := range config.Contexts {
+			if user, ok := config.AuthInfos[ctx.AuthInfo]; ok {
+				if _, ok := kube.IsKtxExec(user.Exec, "exec-cache", "run"); ok {
+					dsts = append(dsts, ctxName)
+				}
+			}
+		}
+	}
+
+	for _, dst := range dsts {
+		key := wrappedExecKey(contextUser(config, dst))
+		if len(key) == 0 {
+			output.Note("Context <%s> exec plugin is not wrapped, skipped.", dst)
+			continue
+		}
+
+		if err := cache.ClearCredential(key); err != nil {
+			output.Fatal("Failed to clear exec cache of context <%s>: %s", dst, err)
+		}
+		output.Done("Context <%s> cached credential cleared.", dst)
+	}
+}
+
+func wrapContextExec(config *clientcmdapi.Config, cache *execcache.Cache, dst string) {
+	user := contextUser(config, dst)
+	if user.Exec == nil {
+		output.Note("Context <%s> does not use an exec plugin, skipped.", dst)
+		return
+	}
+	if _, ok := kube.IsKtxExec(user.Exec, "exec-cache", "run"); ok {
+		output.Note("Context <%s> exec plugin already wrapped, skipped.", dst)
+		return
+	}
+
+	original := user.Exec.DeepCopy()
+	command := original.Command
+	// 相对路径的命令以 kubeconfig 所在目录为基准，与 client-go 的行为一致
+	if !filepath.IsAbs(command) && strings.ContainsRune(command, filepath.Separator) {
+		command = filepath.Join(filepath.Dir(rootFlag.kubeconfig), command)
+	}
+
+	if err := cache.SavePlugin(dst, &execcache.Plugin{Exec: original, Command: command}); err != nil {
+		output.Fatal("Failed to save exec cache of context <%s>: %s", dst, err)
+	}
+
+	// 保持原有 apiVersion 与交互模式，使 kubectl 传递的 KUBERNETES_EXEC_INFO 与原插件一致
+	wrapper := kube.KtxExecConfig("exec-cache", "run", dst)
+	wrapper.APIVersion = original.APIVersion
+	wrapper.InteractiveMode = original.InteractiveMode
+	wrapper.ProvideClusterInfo = original.ProvideClusterInfo
+	user.Exec = wrapper
+
+	kube.SaveConfigToFile(config, rootFlag.kubeconfig)
+	output.Done("Context <%s> exec plugin wrapped.", dst)
+}
+
+func unwrapContextExec(config *clientcmdapi.Config, cache *execcache.Cache, dst string) {
+	user := contextUser(config, dst)
+	key := wrappedExecKey(user)
+	if len(key) == 0 {
+		output.Note("Context <%s> exec plugin is not wrapped, skipped.", dst)
+		return
+	}
+
+	plugin, err := cache.LoadPlugin(key)
+	if err != nil {
+		output.Fatal("Failed to load exec cache of context <%s>: %s", dst, err)
+	}
+
+	user.Exec = plugin.Exec
+	kube.SaveConfigToFile(config, rootFlag.kubeconfig)
+
+	if err := cache.Remove(key); err != nil {
+		output.Fatal("Failed to remove exec cache of context <%s>: %s", dst, err)
+	}
+	output.Done("Context <%s> exec plugin unwrapped.", dst)
+}
+
+// wrappedExecKey returns the cache key of a wrapped user, or empty if the
+// user exec plugin is not wrapped.
+func wrappedExecKey(user *clientcmdapi.AuthInfo) string {
+	args, ok := kube.IsKtxExec(user.Exec, "exec-cache", "run")
+	if !ok || len(args) == 0 {
+		return ""
+	}
+	return args[0]
+}
+
+func runExecCacheRun(key string) {
+	// stdout 仅输出 ExecCredential
+	output.UseStderr()
+
+	cache := execcache.New(execcache.DefaultDir)
+	plugin, err := cache.LoadPlugin(key)
+	if err != nil {
+		output.Fatal("Failed to load exec cache of <%s>: %s", key, err)
+	}
+
+	// 凭据缓存损坏时视为未缓存，重新运行原插件并覆盖
+	if cached, err := cache.LoadCredential(key); err == nil && cached != nil && cached.Valid(time.Now()) {
+		os.Stdout.Write(cached.Raw)
+		return
+	}
+
+	var stdout bytes.Buffer
+	c := exec.Command(plugin.Command, plugin.Exec.Args...)
+	c.Env = os.Environ()
+	for _, env := range plugin.Exec.Env {
+		c.Env = append(c.Env, env.Name+"="+env.Value)
+	}
+	c.Stdin = os.Stdin
+	c.Stdout = &stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+		output.Fatal("Failed to run exec plugin %s: %s", plugin.Command, err)
+	}
+
+	cred, err := execcache.ParseCredential(stdout.Bytes())
+	if err != nil {
+		output.Fatal("Invalid exec credential from %s: %s", plugin.Command, err)
+	}
+	if cred.Expiration != nil {
+		if err := cache.SaveCredential(key, cred); err != nil {
+			output.Fail("Failed to save exec cache of <%s>: %s", key, err)
+		}
+	}
+
+	os.Stdout.Write(stdout.Bytes())
+}
@@ -0,0 +1,155 @@
+/*
+Copyright © 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/ketches/ktx/internal/completion"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/output"
+	"github.com/ketches/ktx/internal/util"
+	"github.com/spf13/cobra"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+type expiryFlags struct {
+	within string
+}
+
+var expiryFlag expiryFlags
+
+// expiryCmd represents the expiry command
+var expiryCmd = &cobra.Command{
+	Use:     "expiry",
+	Aliases: []string{"expires"},
+	Short:   "Show expiry of context certificates and tokens",
+	Long: `Show expiry of the client certificate, CA bundle and JWT bearer token of contexts, all contexts by default.
+
+Exits with code 1 if any credential is expired, or expires within the duration
+specified by --within.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runExpiry(args)
+	},
+	ValidArgsFunction: completion.ContextArray,
+}
+
+func init() {
+	rootCmd.AddCommand(expiryCmd)
+
+	expiryCmd.Flags().StringVar(&expiryFlag.within, "within", "", "Only show credentials expiring within the duration, eg. 30d, 2w, 12h")
+}
+
+func runExpiry(args []string) {
+	config := kube.LoadConfigFromFile(rootFlag.kubeconfig)
+
+	var within time.Duration
+	if len(expiryFlag.within) > 0 {
+		d, err := util.ParseDuration(expiryFlag.within)
+		if err != nil {
+			output.Fatal("Invalid --within: %s", err)
+		}
+		within = d
+	}
+
+	dsts := args
+	if len(dsts) == 0 {
+		dsts = contextNames(config)
+	}
+
+	var (
+		now      = time.Now()
+		expiries []*kube.Expiry
+	)
+	for _, dst := range dsts {
+		if _, ok := config.Contexts[dst]; !ok {
+			output.Fatal("Context <%s> not found.", dst)
+		}
+		for _, expiry := range kube.ContextExpiries(config, rootFlag.kubeconfig, dst) {
+			if within > 0 && expiry.Remaining(now) > within {
+				continue
+			}
+			expiries = append(expiries, expiry)
+		}
+	}
+
+	if len(expiries) == 0 {
+		output.Done(util.If(within > 0, "No credential expires within "+expiryFlag.within+".", "No certificate or JWT token found."))
+		return
+	}
+
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"context", "credential", "subject", "expires", "remaining"})
+	var failed bool
+	for _, expiry := range expiries {
+		remaining := expiry.Remaining(now)
+		if remaining <= 0 || within > 0 {
+			failed = true
+		}
+		t.AppendRow(table.Row{
+			expiry.Context,
+			expiry.Credential,
+			util.If(expiry.Subject == "", "-", expiry.Subject),
+			expiry.NotAfter.Local().Format(time.DateTime),
+			colorRemaining(remaining),
+		})
+	}
+	t.SetStyle(tableStyle)
+	fmt.Println(t.Render())
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// colorRemaining formats the remaining time of a credential, red if expired
+// or critical, yellow if expiring soon.
+func colorRemaining(remaining time.Duration) string {
+	switch {
+	case remaining <= 0:
+		return color.RedString("expired")
+	case remaining < kube.ExpiryCritical:
+		return color.RedString(util.HumanDuration(remaining))
+	case remaining < kube.ExpiryWarning:
+		return color.YellowString(util.HumanDuration(remaining))
+	default:
+		return color.GreenString(util.HumanDuration(remaining))
+	}
+}
+
+// warnExpiry warns if a credential of the context is expired or expires
+// within kube.ExpiryWarning, in red if expired or within kube.ExpiryCritical.
+func warnExpiry(config *clientcmdapi.Config, dst string) {
+	expiries := kube.ContextExpiries(config, rootFlag.kubeconfig, dst)
+	if len(expiries) == 0 {
+		return
+	}
+
+	expiry := expiries[0]
+	switch remaining := expiry.Remaining(time.Now()); {
+	case remaining <= 0:
+		output.Fail("The %s of context <%s> expired at %s.", expiry.Credential, dst, expiry.NotAfter.Local().Format(time.DateTime))
+	case remaining < kube.ExpiryCritical:
+		output.Fail("The %s of context <%s> expires in %s.", expiry.Credential, dst, util.HumanDuration(remaining))
+	case remaining < kube.ExpiryWarning:
+		output.Warn("The %s of context <%s> expires in %s.", expiry.Credential, dst, util.HumanDuration(remaining))
+	}
+}
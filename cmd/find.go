@@ -0,0 +1,159 @@
+/*
+Copyright © 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/ketches/ktx/internal/completion"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/output"
+	"github.com/ketches/ktx/internal/prompt"
+	"github.com/ketches/ktx/internal/util"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+type findFlags struct {
+	contexts []string
+	selector string
+	timeout  time.Duration
+	parallel int
+}
+
+var findFlag findFlags
+
+// findCmd represents the find command
+var findCmd = &cobra.Command{
+	Use:   "find <kind> <name-or-regex>",
+	Short: "Find resources by name across contexts",
+	Long: `Find resources of a kind whose names match a regular expression in all
+namespaces of all contexts, or the contexts specified by --contexts or
+--selector, eg. ktx find pods '^checkout-'.
+
+The kind is a resource name, singular name, short name or kind, optionally
+qualified by the group, eg. po, deploy or deployments.apps. If listing all
+namespaces is forbidden, the namespace of the context is searched. In a
+terminal, a match can be selected to switch to its context and namespace.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		runFind(args)
+	},
+	ValidArgsFunction: completion.None,
+}
+
+func init() {
+	rootCmd.AddCommand(findCmd)
+
+	findCmd.Flags().StringSliceVar(&findFlag.contexts, "contexts", nil, "Contexts to search, comma separated, all contexts by default")
+	findCmd.Flags().StringVarP(&findFlag.selector, "selector", "l", "", "Search contexts matching the label selector, eg. env=prod")
+	findCmd.Flags().DurationVar(&findFlag.timeout, "timeout", 30*time.Second, "Timeout of searching each context")
+	findCmd.Flags().IntVar(&findFlag.parallel, "parallel", 8, "Maximum number of contexts searched concurrently")
+
+	findCmd.RegisterFlagCompletionFunc("contexts", completion.ContextArray)
+}
+
+// findResult is the resources found in a context, or the error finding them.
+type findResult struct {
+	context string
+	matches []*kube.Match
+	err     error
+}
+
+func runFind(args []string) {
+	kind := args[0]
+	pattern, err := regexp.Compile(args[1])
+	if err != nil {
+		output.Fatal("Invalid name pattern <%s>: %s", args[1], err)
+	}
+
+	config := kube.LoadConfigFromFile(rootFlag.kubeconfig)
+	all := len(findFlag.contexts) == 0 && len(findFlag.selector) == 0
+	dsts := selectContexts(config, all, findFlag.contexts, findFlag.selector)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	results := make([]*findResult, len(dsts))
+	for i, dst := range dsts {
+		results[i] = &findResult{context: dst, err: context.Canceled}
+	}
+	util.Parallel(ctx, results, findFlag.parallel, func(ctx context.Context, result *findResult) {
+		result.matches, result.err = kube.Find(ctx, rootFlag.kubeconfig, result.context, kind, pattern, findFlag.timeout)
+	})
+
+	var (
+		failed  bool
+		matches []*kube.Match
+		now     = time.Now()
+		t       = table.NewWriter()
+	)
+	t.AppendHeader(table.Row{"context", "namespace", "kind", "name", "age"})
+	for _, result := range results {
+		if result.err != nil {
+			failed = true
+			status := kube.ClassifyError(result.err).ColorString()
+			if errors.Is(result.err, kube.ErrUnknownResource) {
+				status = color.YellowString("✗ Unknown Kind")
+			}
+			t.AppendRow(table.Row{result.context, status, "-", color.New(color.Faint).Sprint(result.err), "-"})
+			continue
+		}
+		for _, match := range result.matches {
+			matches = append(matches, match)
+			t.AppendRow(table.Row{
+				match.Context,
+				util.If(len(match.Namespace) == 0, "-", match.Namespace),
+				match.Kind,
+				color.CyanString(match.Name),
+				util.If(match.Created.IsZero(), "-", util.HumanDuration(now.Sub(match.Created))),
+			})
+		}
+	}
+
+	if len(matches) == 0 && !failed {
+		output.Note("No %s matching <%s> found in %d context(s).", kind, pattern, len(dsts))
+		os.Exit(1)
+	}
+	t.SetStyle(tableStyle)
+	fmt.Println(t.Render())
+
+	if len(matches) > 0 && term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd())) {
+		items := make([]string, len(matches))
+		for i, match := range matches {
+			items[i] = fmt.Sprintf("%s/%s %s/%s", match.Context, util.If(len(match.Namespace) == 0, "-", match.Namespace), match.Kind, match.Name)
+		}
+		// 选择后切换到资源所在的 context 和 namespace，失败的 context 仍以非零退出码退出
+		if item, ok := prompt.Selection("Switch to the context and namespace of", items, ""); ok {
+			match := matches[slices.Index(items, item)]
+			switchContext(config, match.Context, match.Namespace)
+		}
+	}
+
+	if failed || len(matches) == 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright © 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/ketches/ktx/internal/completion"
+	"github.com/ketches/ktx/internal/output"
+	"github.com/ketches/ktx/internal/util"
+	"github.com/spf13/cobra"
+)
+
+type historyFlags struct {
+	limit int
+	clear bool
+}
+
+var historyFlag historyFlags
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List recent context switches",
+	Long: `List recent context switches, most recent first.
+
+Switch back to the previous context and namespace with "ktx -".`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runHistory()
+	},
+	ValidArgsFunction: completion.None,
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().IntVar(&historyFlag.limit, "limit", 20, "Number of switches to list, 0 for all")
+	historyCmd.Flags().BoolVar(&historyFlag.clear, "clear", false, "Clear the history")
+}
+
+func runHistory() {
+	s := loadState()
+
+	if historyFlag.clear {
+		s.History = nil
+		saveState(s)
+		output.Done("History cleared.")
+		return
+	}
+
+	if len(s.History) == 0 {
+		output.Note("No context switch recorded.")
+		return
+	}
+
+	now := time.Now()
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"time", "", "context", "namespace"})
+	for i := len(s.History) - 1; i >= 0; i-- {
+		if historyFlag.limit > 0 && len(s.History)-i > historyFlag.limit {
+			break
+		}
+		sw := s.History[i]
+		t.AppendRow(table.Row{
+			sw.Time.Local().Format(time.DateTime),
+			util.HumanDuration(now.Sub(sw.Time).Truncate(time.Second)) + " ago",
+			sw.Context,
+			sw.Namespace,
+		})
+	}
+	t.SetStyle(tableStyle)
+	fmt.Println(t.Render())
+}
@@ -0,0 +1,125 @@
+/*
+Copyright © 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ketches/ktx/internal/completion"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/output"
+	"github.com/ketches/ktx/internal/prompt"
+	"github.com/spf13/cobra"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+type impersonateFlags struct {
+	as        string
+	asGroups  []string
+	asUID     string
+	name      string
+	namespace string
+}
+
+var impersonateFlag impersonateFlags
+
+// impersonateCmd represents the impersonate command
+var impersonateCmd = &cobra.Command{
+	Use:   "impersonate",
+	Short: "Create a context impersonating another user from an existing context",
+	Long: `Create a context impersonating another user from an existing context.
+
+The new context reuses the cluster and credentials of the source context and
+sets the impersonation user, groups and uid, so that the cluster can be viewed
+as another user without generating new credentials.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runImpersonate(args)
+	},
+	ValidArgsFunction: completion.Context,
+}
+
+func init() {
+	rootCmd.AddCommand(impersonateCmd)
+
+	impersonateCmd.Flags().StringVar(&impersonateFlag.as, "as", "", "Username to impersonate")
+	impersonateCmd.Flags().StringSliceVar(&impersonateFlag.asGroups, "as-group", nil, "Group to impersonate, can be repeated")
+	impersonateCmd.Flags().StringVar(&impersonateFlag.asUID, "as-uid", "", "UID to impersonate")
+	impersonateCmd.Flags().StringVar(&impersonateFlag.name, "name", "", "Name of the new context, <context>-as-<user> by default")
+	impersonateCmd.Flags().StringVarP(&impersonateFlag.namespace, "namespace", "n", "", "Namespace of the new context, the source context namespace by default")
+
+	impersonateCmd.RegisterFlagCompletionFunc("namespace", completion.Namespace)
+
+	impersonateCmd.MarkFlagRequired("as")
+}
+
+func runImpersonate(args []string) {
+	config := kube.LoadConfigFromFile(rootFlag.kubeconfig)
+
+	var src string
+	if len(args) == 0 {
+		src = prompt.ContextSelection("Select context to impersonate from", config)
+	} else {
+		src = args[0]
+	}
+
+	impersonateContext(config, src)
+}
+
+func impersonateContext(config *clientcmdapi.Config, src string) {
+	srcCtx, ok := config.Contexts[src]
+	if !ok {
+		output.Fatal("Context <%s> not found.", src)
+	}
+
+	srcCluster, ok := config.Clusters[srcCtx.Cluster]
+	if !ok {
+		output.Fatal("Cluster not found for context <%s>.", src)
+	}
+
+	srcUser, ok := config.AuthInfos[srcCtx.AuthInfo]
+	if !ok {
+		output.Fatal("User not found for context <%s>.", src)
+	}
+
+	newCtxName := impersonateFlag.name
+	if len(newCtxName) == 0 {
+		newCtxName = src + "-as-" + impersonateFlag.as
+	}
+	for contextNameConflict(newCtxName, config) {
+		newCtxName = prompt.TextInput(fmt.Sprintf("Context name <%s> already exists, enter a new name", newCtxName), newCtxName)
+	}
+
+	newCtx := srcCtx.DeepCopy()
+	newCtx.Cluster = "cluster-" + newCtxName
+	newCtx.AuthInfo = "user-" + newCtxName
+	if len(impersonateFlag.namespace) > 0 {
+		newCtx.Namespace = impersonateFlag.namespace
+	}
+
+	newUser := srcUser.DeepCopy()
+	newUser.Impersonate = impersonateFlag.as
+	newUser.ImpersonateGroups = impersonateFlag.asGroups
+	newUser.ImpersonateUID = impersonateFlag.asUID
+
+	config.Clusters[newCtx.Cluster] = srcCluster.DeepCopy()
+	config.AuthInfos[newCtx.AuthInfo] = newUser
+	config.Contexts[newCtxName] = newCtx
+
+	kube.SaveConfigToFile(config, rootFlag.kubeconfig)
+	output.Done("Context <%s> added, impersonating <%s> from context <%s>.", newCtxName, impersonateFlag.as, src)
+}
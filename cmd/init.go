@@ -0,0 +1,156 @@
+/*
+Copyright © 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ketches/ktx/internal/output"
+	"github.com/spf13/cobra"
+)
+
+type initFlags struct {
+	prompt   bool
+	perShell bool
+	kubectl  bool
+}
+
+var initFlag initFlags
+
+// initCmd represents the init command
+var initCmd = &cobra.Command{
+	Use:   "init <bash|zsh|fish>",
+	Short: "Print the shell integration script",
+	Long: `Print the shell integration script, which adds the context and namespace to
+the prompt, and wraps ktx in a shell function enabling the per-shell switching
+mode: the first ktx call binds the shell to a session of the current context,
+so switching in one terminal never retargets kubectl in another.
+
+Add to the shell startup file:
+
+  bash (~/.bashrc):                 eval "$(ktx init bash)"
+  zsh (~/.zshrc):                   eval "$(ktx init zsh)"
+  fish (~/.config/fish/config.fish): ktx init fish | source
+
+With --kubectl, kubectl is wrapped to run the kubectl binary matching the
+server version of the context, see "ktx kubectl".`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	Run: func(cmd *cobra.Command, args []string) {
+		runInit(args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+
+	initCmd.Flags().BoolVar(&initFlag.prompt, "prompt", true, "Add the context and namespace to the prompt")
+	initCmd.Flags().BoolVar(&initFlag.perShell, "per-shell", true, "Wrap ktx to switch contexts per shell")
+	initCmd.Flags().BoolVar(&initFlag.kubectl, "kubectl", false, `Wrap kubectl to run the binary matching the cluster version, see "ktx kubectl"`)
+}
+
+const bashPromptScript = `__ktx_prompt() {
+  command ktx prompt --shell bash 2>/dev/null
+}
+if [[ "$PS1" != *'$(__ktx_prompt)'* ]]; then
+  PS1='$(__ktx_prompt) '"$PS1"
+fi
+`
+
+const zshPromptScript = `__ktx_prompt() {
+  command ktx prompt --shell zsh 2>/dev/null
+}
+setopt PROMPT_SUBST
+if [[ "$RPROMPT" != *'$(__ktx_prompt)'* ]]; then
+  RPROMPT='$(__ktx_prompt)'"${RPROMPT:+ $RPROMPT}"
+fi
+`
+
+const fishPromptScript = `function __ktx_prompt
+    command ktx prompt 2>/dev/null
+end
+if functions -q fish_right_prompt; and not functions -q __ktx_original_right_prompt
+    functions -c fish_right_prompt __ktx_original_right_prompt
+end
+function fish_right_prompt
+    __ktx_prompt
+    if functions -q __ktx_original_right_prompt
+        echo -n ' '
+        __ktx_original_right_prompt
+    end
+end
+`
+
+// %[1]s is the shell.
+const posixPerShellScript = `ktx() {
+  case "$1" in
+    env|shell|init|prompt|completion|__complete*) ;;
+    *) [ -n "$KTX_SESSION" ] || eval "$(command ktx env --shell %[1]s 2>/dev/null)" ;;
+  esac
+  command ktx "$@"
+}
+`
+
+const fishPerShellScript = `function ktx
+    switch "$argv[1]"
+        case env shell init prompt completion '__complete*'
+        case '*'
+            if test -z "$KTX_SESSION"
+                command ktx env --shell fish 2>/dev/null | source
+            end
+    end
+    command ktx $argv
+end
+`
+
+// function 关键字定义的函数名不会被同名 alias 展开
+const posixKubectlScript = `function kubectl {
+  command ktx kubectl -- "$@"
+}
+`
+
+const fishKubectlScript = `function kubectl
+    command ktx kubectl -- $argv
+end
+`
+
+func runInit(args []string) {
+	shell := args[0]
+
+	var promptScript, perShellScript, kubectlScript string
+	switch shell {
+	case "bash":
+		promptScript, perShellScript, kubectlScript = bashPromptScript, fmt.Sprintf(posixPerShellScript, shell), posixKubectlScript
+	case "zsh":
+		promptScript, perShellScript, kubectlScript = zshPromptScript, fmt.Sprintf(posixPerShellScript, shell), posixKubectlScript
+	case "fish":
+		promptScript, perShellScript, kubectlScript = fishPromptScript, fishPerShellScript, fishKubectlScript
+	default:
+		output.Fatal("Unsupported shell <%s>, supported: bash, zsh, fish.", shell)
+	}
+
+	fmt.Printf("# ktx shell integration for %s\n", shell)
+	if initFlag.prompt {
+		fmt.Print(promptScript)
+	}
+	if initFlag.perShell {
+		fmt.Print(perShellScript)
+	}
+	if initFlag.kubectl {
+		fmt.Print(kubectlScript)
+	}
+}
@@ -0,0 +1,215 @@
+/*
+Copyright © 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/ketches/ktx/internal/completion"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/output"
+	"github.com/ketches/ktx/internal/types"
+	"github.com/ketches/ktx/internal/util"
+	"github.com/spf13/cobra"
+)
+
+type inventoryFlags struct {
+	contexts []string
+	selector string
+	format   string
+	timeout  time.Duration
+	parallel int
+}
+
+var inventoryFlag inventoryFlags
+
+// inventoryCmd represents the inventory command
+var inventoryCmd = &cobra.Command{
+	Use:   "inventory",
+	Short: "Report the inventory of clusters across contexts",
+	Long: `Report the inventory of the clusters of all contexts, or the contexts specified
+by --contexts or --selector: server version, node count and roles, namespace
+count, and the distribution, cloud provider, CNI and ingress hints.
+
+Distributions are inferred from the server version, node labels and API
+groups, cloud providers from the node providerIDs, CNI plugins and ingress
+controllers from the served API groups. Hints are best effort, eg. a CNI
+without CRDs such as flannel is not detected.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runInventory()
+	},
+	ValidArgsFunction: completion.None,
+}
+
+func init() {
+	rootCmd.AddCommand(inventoryCmd)
+
+	inventoryCmd.Flags().StringSliceVar(&inventoryFlag.contexts, "contexts", nil, "Contexts to report, comma separated, all contexts by default")
+	inventoryCmd.Flags().StringVarP(&inventoryFlag.selector, "selector", "l", "", "Report contexts matching the label selector, eg. env=prod")
+	inventoryCmd.Flags().StringVar(&inventoryFlag.format, "format", "table", "Output format, one of: table, json, csv, markdown, html")
+	inventoryCmd.Flags().DurationVar(&inventoryFlag.timeout, "timeout", 30*time.Second, "Timeout of collecting each context")
+	inventoryCmd.Flags().IntVar(&inventoryFlag.parallel, "parallel", 16, "Maximum number of contexts collected concurrently")
+
+	inventoryCmd.RegisterFlagCompletionFunc("contexts", completion.ContextArray)
+	inventoryCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"table", "json", "csv", "markdown", "html"}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// inventoryResult is the inventory of a context, or the error collecting it.
+type inventoryResult struct {
+	Context string `json:"context"`
+	Status  string `json:"status"`
+	*kube.Inventory
+	Error  string `json:"error,omitempty"`
+	status types.ClusterStatus
+	err    error
+}
+
+func runInventory() {
+	switch inventoryFlag.format {
+	case "table", "json", "csv", "markdown", "html":
+	default:
+		output.Fatal("Unsupported output format <%s>.", inventoryFlag.format)
+	}
+
+	config := kube.LoadConfigFromFile(rootFlag.kubeconfig)
+	all := len(inventoryFlag.contexts) == 0 && len(inventoryFlag.selector) == 0
+	dsts := selectContexts(config, all, inventoryFlag.contexts, inventoryFlag.selector)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	results := make([]*inventoryResult, len(dsts))
+	for i, dst := range dsts {
+		results[i] = &inventoryResult{Context: dst, err: context.Canceled}
+	}
+	util.Parallel(ctx, results, inventoryFlag.parallel, func(ctx context.Context, result *inventoryResult) {
+		result.Inventory, result.err = kube.CollectInventory(ctx, rootFlag.kubeconfig, result.Context, inventoryFlag.timeout)
+	})
+
+	var failed bool
+	for _, result := range results {
+		result.status = kube.ClassifyError(result.err)
+		_, result.Status, _ = strings.Cut(string(result.status), " ")
+		if result.err != nil {
+			failed = true
+			result.Error = result.err.Error()
+		}
+	}
+
+	if inventoryFlag.format == "json" {
+		// 角色中的 <none> 不转义
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			output.Fatal("Failed to marshal inventory: %s", err)
+		}
+	} else {
+		fmt.Println(renderInventory(results, inventoryFlag.format))
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// renderInventory renders the inventory as a table, or a CSV, Markdown or
+// HTML document without colors.
+func renderInventory(results []*inventoryResult, format string) string {
+	colored := format == "table"
+	faint := func(s string) string {
+		return util.If(colored, color.New(color.Faint).Sprint(s), s)
+	}
+	join := func(items []string) string {
+		return util.If(len(items) == 0, "-", strings.Join(items, ", "))
+	}
+	count := func(n *int) string {
+		if n == nil {
+			return "?"
+		}
+		return strconv.Itoa(*n)
+	}
+
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"context", "status", "version", "distribution", "provider", "region", "nodes", "roles", "namespaces", "cni", "ingress", "message"})
+	for _, result := range results {
+		status := util.If(colored, result.status.ColorString(), result.Status)
+		if result.Inventory == nil {
+			t.AppendRow(table.Row{result.Context, status, "-", "-", "-", "-", "-", "-", "-", "-", "-", faint(result.Error)})
+			continue
+		}
+
+		inventory := result.Inventory
+		t.AppendRow(table.Row{
+			result.Context,
+			status,
+			util.If(colored, color.CyanString(inventory.Version), inventory.Version),
+			join(inventory.Distributions),
+			join(inventory.Providers),
+			join(inventory.Regions),
+			count(inventory.Nodes),
+			join(formatRoles(inventory.Roles)),
+			count(inventory.Namespaces),
+			join(inventory.CNI),
+			join(inventory.Ingress),
+			faint(formatWarnings(inventory.Warnings)),
+		})
+	}
+
+	switch format {
+	case "csv":
+		return t.RenderCSV()
+	case "markdown":
+		return t.RenderMarkdown()
+	case "html":
+		return t.RenderHTML()
+	default:
+		t.SetStyle(tableStyle)
+		return t.Render()
+	}
+}
+
+// formatRoles formats the node roles as sorted role:count.
+func formatRoles(roles map[string]int) []string {
+	var items []string
+	for role, n := range roles {
+		items = append(items, fmt.Sprintf("%s:%d", role, n))
+	}
+	sort.Strings(items)
+	return items
+}
+
+// formatWarnings formats the warnings as sorted "what: error".
+func formatWarnings(warnings map[string]string) string {
+	var items []string
+	for what, warning := range warnings {
+		items = append(items, what+": "+warning)
+	}
+	sort.Strings(items)
+	return strings.Join(items, "; ")
+}
@@ -0,0 +1,307 @@
+/*
+Copyright © 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/ketches/ktx/internal/completion"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/kubectl"
+	"github.com/ketches/ktx/internal/lookup"
+	"github.com/ketches/ktx/internal/output"
+	"github.com/ketches/ktx/internal/state"
+	"github.com/ketches/ktx/internal/util"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+type kubectlFlags struct {
+	context  string
+	use      string
+	versions string
+	list     bool
+	timeout  time.Duration
+}
+
+var kubectlFlag kubectlFlags
+
+// kubectlCmd represents the kubectl command
+var kubectlCmd = &cobra.Command{
+	Use:   "kubectl [-- kubectl-args...]",
+	Short: "Run the kubectl binary matching the cluster version",
+	Long: `Run the kubectl binary matching the server version of the current context, or
+the context specified by --context in the kubectl arguments, eg.
+ktx kubectl -- get pods. See "ktx init --kubectl" to run kubectl through it.
+
+The binary is resolved in order:
+
+  1. the binary set for the context by --use
+  2. the binary set for the server version by --use with --versions, the first
+     matching range is used
+  3. the installed kubectl closest to the server version within the supported
+     version skew, found in $PATH, ~/bin and ~/.local/bin, eg. kubectl-1.29
+  4. kubectl in $PATH
+
+The server version is cached for an hour, and a failed request for a minute.
+Installed binaries are only run locally to get their versions, nothing is
+downloaded. Without arguments, the binary resolved for the current context is
+shown.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 && cmd.ArgsLenAtDash() != 0 {
+			return errors.New(`kubectl arguments must follow "--", eg. ktx kubectl -- get pods`)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		runKubectl(cmd, args)
+	},
+	ValidArgsFunction: completion.None,
+}
+
+func init() {
+	rootCmd.AddCommand(kubectlCmd)
+
+	kubectlCmd.Flags().StringVarP(&kubectlFlag.context, "context", "c", "", "Context to set the kubectl binary of by --use, the current context by default")
+	kubectlCmd.Flags().StringVar(&kubectlFlag.use, "use", "", `Set the kubectl binary of the context, or of the server versions with --versions, "" to unset`)
+	kubectlCmd.Flags().StringVar(&kubectlFlag.versions, "versions", "", "Server minor versions to set the kubectl binary of by --use, eg. 1.29, 1.27-1.29, 1.30- or -1.26")
+	kubectlCmd.Flags().BoolVar(&kubectlFlag.list, "list", false, "List installed kubectl binaries and the binaries set")
+	kubectlCmd.Flags().DurationVar(&kubectlFlag.timeout, "timeout", kube.DefaultProbeTimeout, "Timeout of requesting the server version")
+
+	kubectlCmd.RegisterFlagCompletionFunc("context", completion.Context)
+}
+
+func runKubectl(cmd *cobra.Command, args []string) {
+	switch {
+	case cmd.ArgsLenAtDash() == 0:
+		execKubectl(args)
+	case cmd.Flags().Changed("use"):
+		setKubectl()
+	case len(kubectlFlag.versions) > 0:
+		output.Fatal("--versions must be used with --use.")
+	case kubectlFlag.list:
+		listKubectl()
+	default:
+		showKubectl()
+	}
+}
+
+// execKubectl runs the resolved kubectl binary with the arguments, and exits
+// with its exit code.
+func execKubectl(args []string) {
+	// 标准输出留给 kubectl
+	output.UseStderr()
+
+	file, ctxName := kubectlTarget(args)
+	path := kubectlPath(file, ctxName).Path
+
+	c := exec.Command(path, args...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	// 终端的 Ctrl-C 同时发送给 kubectl，由 kubectl 决定如何退出
+	signal.Notify(make(chan os.Signal, 1), os.Interrupt)
+
+	err := c.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.ExitCode())
+	} else if err != nil {
+		output.Fatal("Failed to run %s: %s", path, err)
+	}
+}
+
+// kubectlTarget returns the kubeconfig and context kubectl targets with the
+// arguments. The kubeconfig is empty if kubectl loads $KUBECONFIG or
+// ~/.kube/config, which is the session kubeconfig in a ktx session.
+func kubectlTarget(args []string) (string, string) {
+	file, ctxName := kubectl.ParseTarget(args)
+	if len(ctxName) > 0 {
+		return file, ctxName
+	}
+
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = file
+	config, err := rules.Load()
+	if err != nil {
+		return file, ""
+	}
+	return file, config.CurrentContext
+}
+
+// kubectlPath resolves the kubectl binary for the context, see kubectlCmd.
+func kubectlPath(file, ctxName string) *kubectl.Resolution {
+	ctx := context.Background()
+	resolution, err := resolveKubectl(ctx, ctxName, func() string {
+		return lookup.ServerVersion(ctx, file, ctxName, kubectlFlag.timeout)
+	})
+	if err != nil {
+		output.Fatal("kubectl not found in $PATH, and no kubectl is set for context <%s>.", ctxName)
+	}
+	return resolution
+}
+
+// resolveKubectl resolves the kubectl binary for the context, see kubectlCmd.
+// server returns the server version of the context, "" if unknown, and is
+// only called if no kubectl is set for the context.
+func resolveKubectl(ctx context.Context, ctxName string, server func() string) (*kubectl.Resolution, error) {
+	// 状态文件损坏时仍然可以运行 kubectl
+	s, err := state.Load(state.DefaultFile)
+	if err != nil {
+		s = &state.State{}
+	}
+
+	var contextPath, version string
+	if meta, ok := s.Contexts[ctxName]; ok {
+		contextPath = meta.Kubectl
+	}
+	if len(contextPath) == 0 && len(ctxName) > 0 {
+		version = server()
+	}
+	discover := func() []kubectl.Binary {
+		return kubectl.Discover(ctx, kubectl.Dirs())
+	}
+	if resolution := kubectl.Resolve(contextPath, s.Kubectl, version, discover); resolution != nil {
+		return resolution, nil
+	}
+
+	path, err := kubectl.Lookup()
+	if err != nil {
+		return nil, err
+	}
+	reason := "kubectl in $PATH"
+	if len(version) == 0 && len(ctxName) > 0 {
+		reason += ", the server version is unknown"
+	} else if len(version) > 0 {
+		reason += ", no kubectl set or installed for server " + version
+	}
+	return &kubectl.Resolution{Path: path, Reason: reason}, nil
+}
+
+// setKubectl sets the kubectl binary of the context or the server versions.
+func setKubectl() {
+	path := kubectlFlag.use
+	var version string
+	if len(path) > 0 {
+		var err error
+		if path, err = filepath.Abs(path); err != nil {
+			output.Fatal("Invalid kubectl path <%s>: %s", kubectlFlag.use, err)
+		}
+		if version, err = kubectl.Version(context.Background(), path); err != nil {
+			output.Fatal("Failed to get the version of <%s>: %s", path, err)
+		}
+	}
+
+	s := loadState()
+	if len(kubectlFlag.versions) > 0 {
+		if _, err := kubectl.ParseRange(kubectlFlag.versions); err != nil {
+			output.Fatal("%s", err)
+		}
+		s.SetKubectlRule(kubectlFlag.versions, path)
+		saveState(s)
+		if len(path) == 0 {
+			output.Done("kubectl of server versions <%s> unset.", kubectlFlag.versions)
+			return
+		}
+		output.Done("kubectl of server versions <%s> set to <%s> (%s).", kubectlFlag.versions, path, version)
+		return
+	}
+
+	ctxName := kubectlFlag.context
+	if len(ctxName) == 0 {
+		ctxName, _ = currentContext(kube.LoadConfigFromFile(rootFlag.kubeconfig))
+	} else if _, ok := kube.LoadConfigFromFile(rootFlag.kubeconfig).Contexts[ctxName]; !ok {
+		output.Fatal("Context <%s> not found.", ctxName)
+	}
+	if len(ctxName) == 0 {
+		output.Fatal("No current context, specify the context by --context.")
+	}
+	s.Context(ctxName).Kubectl = path
+	saveState(s)
+	if len(path) == 0 {
+		output.Done("kubectl of context <%s> unset.", ctxName)
+		return
+	}
+	output.Done("kubectl of context <%s> set to <%s> (%s).", ctxName, path, version)
+}
+
+// showKubectl shows the kubectl binary resolved for the current context.
+func showKubectl() {
+	file, ctxName := kubectlTarget(nil)
+	resolution := kubectlPath(file, ctxName)
+
+	version, err := kubectl.Version(context.Background(), resolution.Path)
+	if err != nil {
+		version = color.RedString("unknown: %s", err)
+	}
+
+	field := func(name, value string) {
+		fmt.Printf("%s %s\n", color.New(color.Faint).Sprintf("%-8s", name+":"), value)
+	}
+	field("Context", util.If(len(ctxName) > 0, ctxName, "-"))
+	field("Kubectl", color.CyanString(resolution.Path)+" "+version)
+	field("Reason", resolution.Reason)
+}
+
+// listKubectl lists the installed kubectl binaries and the binaries set for
+// contexts and server versions.
+func listKubectl() {
+	binaries := kubectl.Discover(context.Background(), kubectl.Dirs())
+	if len(binaries) == 0 {
+		output.Note("No kubectl installed in $PATH, ~/bin or ~/.local/bin.")
+	} else {
+		t := table.NewWriter()
+		t.AppendHeader(table.Row{"kubectl", "version"})
+		for _, binary := range binaries {
+			t.AppendRow(table.Row{binary.Path, color.CyanString(binary.Version)})
+		}
+		t.SetStyle(tableStyle)
+		fmt.Println(t.Render())
+	}
+
+	s := loadState()
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"for", "kubectl"})
+	var names []string
+	for name, meta := range s.Contexts {
+		if len(meta.Kubectl) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		t.AppendRow(table.Row{"context " + name, s.Contexts[name].Kubectl})
+	}
+	for _, rule := range s.Kubectl {
+		t.AppendRow(table.Row{"versions " + rule.Versions, rule.Path})
+	}
+	if t.Length() == 0 {
+		output.Note("No kubectl set, the installed kubectl closest to the server version is used.")
+		return
+	}
+	t.SetStyle(tableStyle)
+	fmt.Println(t.Render())
+}
@@ -0,0 +1,128 @@
+/*
+Copyright © 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/ketches/ktx/internal/completion"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/output"
+	"github.com/ketches/ktx/internal/state"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// labelCmd represents the label command
+var labelCmd = &cobra.Command{
+	Use:   "label [context] [key=value ...] [key- ...]",
+	Short: "Update the labels of a context",
+	Long: `Update the labels of a context, or show the labels of all contexts without arguments.
+
+Labels are kept in the ktx state file (~/.kube/ktx/state.yaml) instead of the
+kubeconfig, and select contexts with --selector, eg. env=prod.`,
+	Example: `  ktx label prod-eu env=prod region=eu
+  ktx label prod-eu region-`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runLabel(args)
+	},
+	ValidArgsFunction: completion.Context,
+}
+
+func init() {
+	rootCmd.AddCommand(labelCmd)
+}
+
+func runLabel(args []string) {
+	config := kube.LoadConfigFromFile(rootFlag.kubeconfig)
+	s := loadState()
+
+	if len(args) == 0 {
+		printLabels(s, contextNames(config))
+		return
+	}
+
+	dst := args[0]
+	if _, ok := config.Contexts[dst]; !ok {
+		output.Fatal("Context <%s> not found.", dst)
+	}
+	if len(args) == 1 {
+		printLabels(s, []string{dst})
+		return
+	}
+
+	ctx := s.Context(dst)
+	for _, arg := range args[1:] {
+		if key, ok := strings.CutSuffix(arg, "-"); ok && !strings.Contains(arg, "=") {
+			delete(ctx.Labels, key)
+			continue
+		}
+
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			output.Fatal("Invalid label <%s>, expected key=value or key-.", arg)
+		}
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			output.Fatal("Invalid label key <%s>: %s", key, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			output.Fatal("Invalid label value <%s>: %s", value, strings.Join(errs, "; "))
+		}
+		if ctx.Labels == nil {
+			ctx.Labels = make(map[string]string)
+		}
+		ctx.Labels[key] = value
+	}
+
+	saveState(s)
+	output.Done("Context <%s> labeled.", dst)
+}
+
+func printLabels(s *state.State, names []string) {
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"context", "labels"})
+	for _, name := range names {
+		var labels []string
+		if ctx, ok := s.Contexts[name]; ok {
+			for k, v := range ctx.Labels {
+				labels = append(labels, k+"="+v)
+			}
+		}
+		sort.Strings(labels)
+		t.AppendRow(table.Row{name, strings.Join(labels, ",")})
+	}
+	t.SetStyle(tableStyle)
+	fmt.Println(t.Render())
+}
+
+// loadState loads the ktx state, and exits if it fails.
+func loadState() *state.State {
+	s, err := state.Load(state.DefaultFile)
+	if err != nil {
+		output.Fatal("Failed to load ktx state: %s", err)
+	}
+	return s
+}
+
+func saveState(s *state.State) {
+	if err := s.Save(); err != nil {
+		output.Fatal("Failed to save ktx state: %s", err)
+	}
+}
@@ -17,7 +17,11 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"os/signal"
+	"strings"
 	"sync"
 	"time"
 
@@ -30,10 +34,15 @@ import (
 	"github.com/ketches/ktx/internal/types"
 	"github.com/ketches/ktx/internal/util"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 type listFlags struct {
 	clusterInfo bool
+	expires     bool
+	verbose     bool
+	timeout     time.Duration
+	parallel    int
 }
 
 var listFlag listFlags
@@ -54,6 +63,10 @@ func init() {
 	rootCmd.AddCommand(listCmd)
 
 	listCmd.Flags().BoolVar(&listFlag.clusterInfo, "cluster-info", false, "Show cluster info eg. status, version, and more.")
+	listCmd.Flags().BoolVar(&listFlag.expires, "expires", false, "Show the time remaining until the earliest credential of the context expires.")
+	listCmd.Flags().BoolVarP(&listFlag.verbose, "verbose", "v", false, "Show the underlying error message of cluster info.")
+	listCmd.Flags().DurationVar(&listFlag.timeout, "timeout", kube.DefaultProbeTimeout, "Timeout of probing each cluster.")
+	listCmd.Flags().IntVar(&listFlag.parallel, "parallel", 16, "Maximum number of clusters probed concurrently.")
 }
 
 func runList() {
@@ -65,6 +78,14 @@ func runList() {
 		return
 	}
 
+	if listFlag.expires {
+		for _, ctx := range ctxs {
+			if expiries := kube.ContextExpiries(config, rootFlag.kubeconfig, ctx.Name); len(expiries) > 0 {
+				ctx.Expiry = expiries[0].NotAfter
+			}
+		}
+	}
+
 	listContexts(ctxs)
 
 	// 如果当前没有 context，那么提示用户选择一个 context
@@ -86,70 +107,108 @@ var tableStyle = table.Style{
 }
 
 func listContexts(ctxs []*types.ContextProfile) {
+	if !listFlag.clusterInfo {
+		fmt.Println(renderContexts(ctxs))
+		return
+	}
+
+	for _, ctx := range ctxs {
+		ctx.ClusterStatus = types.ClusterStatusProbing
+	}
+
+	probeCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// 终端中每得到一个探测结果就原地重绘表格，否则只在最后输出一次
+	var (
+		mu    sync.Mutex
+		tty   = term.IsTerminal(int(os.Stdout.Fd()))
+		lines int
+	)
+	render := func() {
+		out := renderContexts(ctxs) + "\n"
+		if lines > 0 {
+			fmt.Printf("\033[%dA\033[J", lines)
+		}
+		fmt.Print(out)
+		lines = strings.Count(out, "\n")
+	}
+	if tty {
+		render()
+	}
+
+	util.Parallel(probeCtx, ctxs, listFlag.parallel, func(c context.Context, ctx *types.ContextProfile) {
+		result := kube.Probe(c, rootFlag.kubeconfig, ctx.Name, listFlag.timeout)
+
+		mu.Lock()
+		defer mu.Unlock()
+		ctx.ClusterStatus = result.Status
+		ctx.ClusterVersion = result.Version
+		ctx.ClusterLatency = result.Latency
+		if result.Err != nil {
+			ctx.ClusterMessage = result.Err.Error()
+		}
+		if tty {
+			render()
+		}
+	})
+
+	// 被中断时未开始探测的 context
+	for _, ctx := range ctxs {
+		if ctx.ClusterStatus == types.ClusterStatusProbing {
+			ctx.ClusterStatus = types.ClusterStatusCanceled
+		}
+	}
+	render()
+}
+
+func renderContexts(ctxs []*types.ContextProfile) string {
 	t := table.NewWriter()
-	t.SetOutputMirror(os.Stdout)
 	row := table.Row{"", "name", "namespace", "server"}
+	if listFlag.expires {
+		row = append(row, "expires")
+	}
 	if listFlag.clusterInfo {
-		row = append(row, "status", "version")
-
-		var wg sync.WaitGroup
-		for i, ctx := range ctxs {
-			wg.Add(1)
-			go func(i int, ctx *types.ContextProfile) {
-				defer wg.Done()
-
-				var (
-					clusterStatus  = types.ClusterStatusUnavailable
-					clusterVersion string
-				)
-				done := make(chan struct{})
-				go func() {
-					defer close(done)
-					dc, _ := kube.DiscoveryClient(rootFlag.kubeconfig, ctx.Name)
-					if dc != nil {
-						cv, _ := kube.ServerVersion(dc)
-						if cv != "" {
-							clusterStatus = types.ClusterStatusAvailable
-							clusterVersion = cv
-						}
-					}
-				}()
-
-				timer := time.NewTimer(time.Second * 2)
-				defer timer.Stop()
-
-				select {
-				case <-timer.C:
-					clusterStatus = types.ClusterStatusTimeout
-				case <-done:
-				}
-
-				ctx.ClusterStatus = clusterStatus
-				ctx.ClusterVersion = clusterVersion
-			}(i, ctx)
+		row = append(row, "status", "version", "latency")
+		if listFlag.verbose {
+			row = append(row, "message")
 		}
-		wg.Wait()
 	}
-
 	t.AppendHeader(row)
 
 	for _, ctx := range ctxs {
 		appendRow(t, ctx)
 	}
 	t.SetStyle(tableStyle)
-	t.Render()
+	return t.Render()
 }
 
 func appendRow(t table.Writer, ctx *types.ContextProfile) {
+	var (
+		emoji     = ctx.Emoji
+		name      = ctx.Name
+		namespace = ctx.Namespace
+		server    = ctx.Server
+	)
 	if ctx.Current {
-		ctx.Name = color.CyanString(ctx.Name)
-		ctx.Namespace = color.CyanString(ctx.Namespace)
-		ctx.Server = color.CyanString(ctx.Server)
-		ctx.Emoji = color.CyanString(ctx.Emoji)
+		name = color.CyanString(name)
+		namespace = color.CyanString(namespace)
+		server = color.CyanString(server)
+		emoji = color.CyanString(emoji)
+	}
+	if len(ctx.Impersonate) > 0 {
+		name += " " + color.MagentaString("(as %s)", ctx.Impersonate)
+	}
+	row := table.Row{emoji, name, namespace, server}
+	if listFlag.expires {
+		row = append(row, util.If(ctx.Expiry.IsZero(), "-", colorRemaining(time.Until(ctx.Expiry))))
 	}
-	row := table.Row{ctx.Emoji, ctx.Name, ctx.Namespace, ctx.Server}
 	if listFlag.clusterInfo {
-		row = append(row, string(ctx.ClusterStatus.ColorString()), util.If(ctx.ClusterVersion == "", "-", color.CyanString(ctx.ClusterVersion)))
+		row = append(row, string(ctx.ClusterStatus.ColorString()), util.If(ctx.ClusterVersion == "", "-", color.CyanString(ctx.ClusterVersion)),
+			util.If(ctx.ClusterLatency == 0, "-", ctx.ClusterLatency.Round(time.Millisecond).String()))
+		if listFlag.verbose {
+			row = append(row, util.If(ctx.ClusterMessage == "", "-", color.New(color.Faint).Sprint(ctx.ClusterMessage)))
+		}
 	}
 	t.AppendRow(row, table.RowConfig{
 		AutoMerge: true,
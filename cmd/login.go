@@ -0,0 +1,209 @@
+/*
+Copyright © 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+
+	"github.com/ketches/ktx/internal/completion"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/oidc"
+	"github.com/ketches/ktx/internal/output"
+	"github.com/ketches/ktx/internal/prompt"
+	"github.com/ketches/ktx/internal/vault"
+	"github.com/spf13/cobra"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+type loginFlags struct {
+	issuerURL    string
+	clientID     string
+	clientSecret string
+	scopes       []string
+	device       bool
+	listenPort   int
+}
+
+var loginFlag loginFlags
+
+// loginCmd represents the login command
+var loginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Login to a context with OpenID Connect",
+	Long: `Login to a context with OpenID Connect.
+
+The authorization code flow with PKCE is used by default, the browser is
+redirected back to a loopback address. Use --device for the device code flow on
+machines without a browser. The refresh token is stored in the ktx vault, and
+the context authenticates through the "ktx credential" exec plugin.
+
+Issuer and client are read from the deprecated oidc auth-provider of the context
+when not specified.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runLogin(args)
+	},
+	ValidArgsFunction: completion.Context,
+}
+
+func init() {
+	rootCmd.AddCommand(loginCmd)
+
+	loginCmd.Flags().StringVar(&loginFlag.issuerURL, "issuer-url", "", "OIDC issuer URL")
+	loginCmd.Flags().StringVar(&loginFlag.clientID, "client-id", "", "OIDC client ID")
+	loginCmd.Flags().StringVar(&loginFlag.clientSecret, "client-secret", "", "OIDC client secret, for confidential clients")
+	loginCmd.Flags().StringSliceVar(&loginFlag.scopes, "scope", nil, "Additional scopes to request, eg. offline_access, groups")
+	loginCmd.Flags().BoolVar(&loginFlag.device, "device", false, "Use the device code flow")
+	loginCmd.Flags().IntVar(&loginFlag.listenPort, "listen-port", 8000, "Loopback port receiving the authorization code redirect")
+}
+
+func runLogin(args []string) {
+	config := kube.LoadConfigFromFile(rootFlag.kubeconfig)
+
+	var dst string
+	if len(args) == 0 {
+		dst = prompt.ContextSelection("Select context to login", config)
+	} else {
+		dst = args[0]
+	}
+
+	loginContext(config, dst)
+}
+
+func loginContext(config *clientcmdapi.Config, dst string) {
+	user := contextUser(config, dst)
+
+	key := dst
+	if args, ok := kube.IsKtxExec(user.Exec, "credential"); ok && len(args) > 0 {
+		key = args[0]
+	}
+
+	v := openVault()
+	login := loginSettings(user, v.Entries[key])
+	if len(login.Issuer) == 0 || len(login.ClientID) == 0 {
+		output.Fatal("Issuer URL and client ID are required, specify them with --issuer-url and --client-id.")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	client := oidcClient(login)
+	client.ListenPort = loginFlag.listenPort
+
+	var (
+		token *oidc.Token
+		err   error
+	)
+	if loginFlag.device {
+		token, err = client.DeviceLogin(ctx, func(da *oidc.DeviceAuthorization) {
+			if len(da.VerificationURIComplete) > 0 {
+				output.Note("Open %s to login.", da.VerificationURIComplete)
+			} else {
+				output.Note("Open %s and enter code %s to login.", da.VerificationURI, da.UserCode)
+			}
+		})
+	} else {
+		token, err = client.AuthCodeLogin(ctx, func(authURL string) {
+			output.Note("Open %s to login if the browser does not open.", authURL)
+			openBrowser(authURL)
+		})
+	}
+	if err != nil {
+		output.Fatal("Failed to login context <%s>: %s", dst, err)
+	}
+
+	login.IDToken = token.IDToken
+	login.RefreshToken = token.RefreshToken
+	login.Expiry = token.Expiry
+	v.Entries[key] = &vault.Entry{OIDC: login}
+	if err := v.Save(); err != nil {
+		output.Fatal("Failed to save vault: %s", err)
+	}
+
+	user.AuthProvider = nil
+	user.Token = ""
+	user.TokenFile = ""
+	user.Exec = kube.KtxExecConfig("credential", key)
+	kube.SaveConfigToFile(config, rootFlag.kubeconfig)
+
+	if len(token.RefreshToken) == 0 {
+		output.Note("No refresh token issued, request the offline_access scope to avoid logging in again when the token expires.")
+	}
+	output.Done("Context <%s> logged in.", dst)
+}
+
+// loginSettings merges the login flags with the previous login stored in the
+// vault and the deprecated oidc auth-provider of the user.
+func loginSettings(user *clientcmdapi.AuthInfo, previous *vault.Entry) *vault.OIDC {
+	login := &vault.OIDC{}
+	if previous != nil && previous.OIDC != nil {
+		login.Issuer = previous.OIDC.Issuer
+		login.ClientID = previous.OIDC.ClientID
+		login.ClientSecret = previous.OIDC.ClientSecret
+		login.Scopes = previous.OIDC.Scopes
+	} else if user.AuthProvider != nil && user.AuthProvider.Name == "oidc" {
+		login.Issuer = user.AuthProvider.Config["idp-issuer-url"]
+		login.ClientID = user.AuthProvider.Config["client-id"]
+		login.ClientSecret = user.AuthProvider.Config["client-secret"]
+		if scopes := user.AuthProvider.Config["extra-scopes"]; len(scopes) > 0 {
+			login.Scopes = strings.Split(scopes, ",")
+		}
+	}
+
+	if len(loginFlag.issuerURL) > 0 {
+		login.Issuer = loginFlag.issuerURL
+	}
+	if len(loginFlag.clientID) > 0 {
+		login.ClientID = loginFlag.clientID
+	}
+	if len(loginFlag.clientSecret) > 0 {
+		login.ClientSecret = loginFlag.clientSecret
+	}
+	if len(loginFlag.scopes) > 0 {
+		login.Scopes = loginFlag.scopes
+	}
+	return login
+}
+
+func oidcClient(login *vault.OIDC) *oidc.Client {
+	return &oidc.Client{
+		Issuer:       login.Issuer,
+		ClientID:     login.ClientID,
+		ClientSecret: login.ClientSecret,
+		Scopes:       login.Scopes,
+	}
+}
+
+// openBrowser opens the URL in the default browser, failures are ignored
+// since the URL is printed as well.
+func openBrowser(url string) {
+	var c *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		c = exec.Command("open", url)
+	case "windows":
+		c = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		c = exec.Command("xdg-open", url)
+	}
+	c.Start()
+}
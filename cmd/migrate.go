@@ -0,0 +1,139 @@
+/*
+Copyright © 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/ketches/ktx/internal/completion"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/output"
+	"github.com/ketches/ktx/internal/prompt"
+	"github.com/ketches/ktx/internal/util"
+	"github.com/spf13/cobra"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+type migrateFlags struct {
+	yes    bool
+	dryRun bool
+}
+
+var migrateFlag migrateFlags
+
+// migrateCmd represents the migrate command
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Migrate deprecated auth-provider and exec API versions",
+	Long: `Migrate deprecated auth-provider and exec API versions of contexts, all contexts by default.
+
+The removed gcp, azure and oidc auth-providers are replaced by the
+gke-gcloud-auth-plugin, kubelogin and kubectl oidc-login exec plugins, and
+exec stanzas using client.authentication.k8s.io/v1alpha1 or v1beta1 are moved
+to client.authentication.k8s.io/v1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runMigrate(args)
+	},
+	ValidArgsFunction: completion.ContextArray,
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+
+	migrateCmd.Flags().BoolVarP(&migrateFlag.yes, "yes", "y", false, "Migrate without confirmation")
+	migrateCmd.Flags().BoolVar(&migrateFlag.dryRun, "dry-run", false, "Only show the changes")
+}
+
+func runMigrate(args []string) {
+	config := kube.LoadConfigFromFile(rootFlag.kubeconfig)
+
+	dsts := args
+	if len(dsts) == 0 {
+		dsts = contextNames(config)
+	}
+
+	found, changed := migrateContexts(config, dsts, migrateFlag.yes, migrateFlag.dryRun)
+	if !found {
+		output.Done("No deprecated auth configuration found.")
+		return
+	}
+	if changed {
+		kube.SaveConfigToFile(config, rootFlag.kubeconfig)
+	}
+}
+
+// migrateContexts migrates the deprecated users of the contexts in place,
+// found reports if any deprecated user is found and changed if any is
+// migrated.
+func migrateContexts(config *clientcmdapi.Config, dsts []string, yes, dryRun bool) (found, changed bool) {
+	for _, dst := range dsts {
+		ctx, ok := config.Contexts[dst]
+		if !ok {
+			output.Fatal("Context <%s> not found.", dst)
+		}
+		user, ok := config.AuthInfos[ctx.AuthInfo]
+		if !ok {
+			continue
+		}
+
+		migrated, reasons, ok := kube.MigrateUser(user)
+		if !ok {
+			continue
+		}
+		found = true
+
+		output.Note("Context <%s>: %s.", dst, strings.Join(reasons, "; "))
+		before, after := kube.MarshalUser(user), kube.MarshalUser(migrated)
+		if before == after {
+			continue
+		}
+		printDiff(before, after)
+
+		if dryRun || (!yes && !prompt.YesNo(fmt.Sprintf("Migrate context %s", dst))) {
+			continue
+		}
+
+		config.AuthInfos[ctx.AuthInfo] = migrated
+		changed = true
+		output.Done("Context <%s> migrated.", dst)
+	}
+	return found, changed
+}
+
+func printDiff(before, after string) {
+	for _, line := range util.DiffLines(before, after) {
+		switch line[0] {
+		case '-':
+			color.Red("%s", line)
+		case '+':
+			color.Green("%s", line)
+		default:
+			color.New(color.Faint).Println(line)
+		}
+	}
+}
+
+// contextNames returns the sorted context names of the kubeconfig.
+func contextNames(config *clientcmdapi.Config) []string {
+	var names []string
+	for _, ctx := range kube.ListContexts(config) {
+		names = append(names, ctx.Name)
+	}
+	return names
+}
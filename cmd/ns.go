@@ -0,0 +1,344 @@
+/*
+Copyright © 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/ketches/ktx/internal/completion"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/lookup"
+	"github.com/ketches/ktx/internal/output"
+	"github.com/ketches/ktx/internal/prompt"
+	"github.com/ketches/ktx/internal/session"
+	"github.com/ketches/ktx/internal/util"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+type nsFlags struct {
+	context     string
+	known       []string
+	allContexts bool
+	contexts    []string
+	selector    string
+	filter      string
+	timeout     time.Duration
+	parallel    int
+}
+
+var nsFlag nsFlags
+
+// nsCmd represents the ns command
+var nsCmd = &cobra.Command{
+	Use:     "ns [namespace|-]",
+	Aliases: []string{"namespace"},
+	Short:   "Switch namespace of the current context",
+	Long: `Switch namespace of the current context, or the context specified by --context.
+
+Select the namespace interactively if not specified, the namespace is checked
+to exist before switching. Use "ktx ns -" to switch back to the previous
+namespace of the context.
+
+If the namespaces can not be listed, eg. forbidden by RBAC, the namespaces are
+found from the cache, the namespaces known in ktx metadata set by --known, and
+access reviews and role bindings, or input as free text.
+
+With --all-contexts, --contexts or --selector, the namespaces of the contexts
+are listed concurrently, filtered by --filter, and one of them can be switched
+to directly, eg. ktx ns --all-contexts --filter '^payments'.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runNs(cmd, args)
+	},
+	ValidArgsFunction: completion.Namespace,
+}
+
+func init() {
+	rootCmd.AddCommand(nsCmd)
+
+	nsCmd.Flags().StringVarP(&nsFlag.context, "context", "c", "", "Context, the current context by default")
+	nsCmd.Flags().StringSliceVar(&nsFlag.known, "known", nil, "Set the namespaces known in the context, listed when namespaces can not be listed")
+	nsCmd.Flags().BoolVar(&nsFlag.allContexts, "all-contexts", false, "List namespaces of all contexts")
+	nsCmd.Flags().StringSliceVar(&nsFlag.contexts, "contexts", nil, "List namespaces of the contexts, comma separated")
+	nsCmd.Flags().StringVarP(&nsFlag.selector, "selector", "l", "", "List namespaces of contexts matching the label selector, eg. env=prod")
+	nsCmd.Flags().StringVar(&nsFlag.filter, "filter", "", "Regular expression the listed namespaces match")
+	nsCmd.Flags().DurationVar(&nsFlag.timeout, "timeout", 10*time.Second, "Timeout of listing each context")
+	nsCmd.Flags().IntVar(&nsFlag.parallel, "parallel", 16, "Maximum number of contexts listed concurrently")
+
+	nsCmd.RegisterFlagCompletionFunc("context", completion.Context)
+	nsCmd.RegisterFlagCompletionFunc("contexts", completion.ContextArray)
+}
+
+func runNs(cmd *cobra.Command, args []string) {
+	config := kube.LoadConfigFromFile(rootFlag.kubeconfig)
+
+	if nsFlag.allContexts || len(nsFlag.contexts) > 0 || len(nsFlag.selector) > 0 {
+		if len(args) > 0 {
+			output.Fatal("Use --filter to match namespaces across contexts.")
+		}
+		listContextsNamespaces(config)
+		return
+	}
+
+	current, currentNamespace := currentContext(config)
+	ctxName := util.If(len(nsFlag.context) > 0, nsFlag.context, current)
+	ctx, ok := config.Contexts[ctxName]
+	if !ok {
+		output.Fatal("Context <%s> not found.", ctxName)
+	}
+
+	if cmd.Flags().Changed("known") {
+		setKnownNamespaces(ctxName, nsFlag.known)
+		return
+	}
+
+	namespace := currentNamespace
+	if ctxName != current {
+		namespace = util.If(len(ctx.Namespace) > 0, ctx.Namespace, kube.DefaultNamespace)
+	}
+
+	var dst string
+	switch {
+	case len(args) == 0:
+		dst = selectNamespace("Switch to namespace of context <"+ctxName+">", ctxName, namespace)
+	case args[0] == "-":
+		dst = loadState().PreviousNamespace(ctxName, namespace)
+		if len(dst) == 0 {
+			output.Fatal("No previous namespace of context <%s> to switch back to.", ctxName)
+		}
+	default:
+		dst = args[0]
+		checkNamespace(ctxName, dst)
+	}
+
+	switchNamespace(config, ctxName, namespace, dst)
+}
+
+// otherNamespace is the item to input a namespace not listed.
+const otherNamespace = "✎ Other namespace"
+
+// selectNamespace selects a namespace of the context, noting why if the
+// namespaces listed are partial.
+func selectNamespace(label, ctxName, current string) string {
+	list := lookup.Namespaces(context.Background(), rootFlag.kubeconfig, ctxName, 10*time.Second)
+	items := list.Items
+	if list.Err != nil {
+		output.Warn("Unable to list namespaces of context <%s>: %s", ctxName, list.Err)
+		if len(items) == 0 {
+			return prompt.TextInput("Namespace", current)
+		}
+		output.Note("The namespaces listed are partial, found from %s.", strings.Join(list.Sources, ", "))
+		items = append(items, otherNamespace)
+	}
+
+	dst := prompt.NamespaceSelection(label, items, current)
+	if dst == otherNamespace {
+		return prompt.TextInput("Namespace", current)
+	}
+	return dst
+}
+
+// setKnownNamespaces sets the namespaces known in the context.
+func setKnownNamespaces(ctxName string, namespaces []string) {
+	namespaces = slices.DeleteFunc(slices.Clone(namespaces), func(ns string) bool {
+		return len(ns) == 0
+	})
+	slices.Sort(namespaces)
+	namespaces = slices.Compact(namespaces)
+
+	s := loadState()
+	s.Context(ctxName).Namespaces = namespaces
+	saveState(s)
+	if len(namespaces) == 0 {
+		output.Done("Known namespaces of context <%s> cleared.", ctxName)
+		return
+	}
+	output.Done("Known namespaces of context <%s> set to %s.", ctxName, strings.Join(namespaces, ", "))
+}
+
+// checkNamespace exits if the namespace does not exist in the context,
+// suggesting similar namespaces. The namespace is not checked if the user is
+// not allowed to get it.
+func checkNamespace(ctxName, namespace string) {
+	clientset := kube.ClientOrDie(rootFlag.kubeconfig, ctxName)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err == nil {
+		return
+	}
+	if !apierrors.IsNotFound(err) {
+		output.Warn("Unable to check namespace <%s>: %s", namespace, err)
+		return
+	}
+
+	list := lookup.Namespaces(ctx, rootFlag.kubeconfig, ctxName, 10*time.Second)
+	if suggestions := util.Suggest(namespace, list.Items); len(suggestions) > 0 {
+		output.Fatal("Namespace <%s> not found in context <%s>, did you mean: %s?", namespace, ctxName, strings.Join(suggestions, ", "))
+	}
+	output.Fatal("Namespace <%s> not found in context <%s>.", namespace, ctxName)
+}
+
+// switchNamespace switches the namespace of the context, in the session if the
+// shell is in one of the context.
+func switchNamespace(config *clientcmdapi.Config, ctxName, from, to string) {
+	if from == to {
+		output.Done("Context <%s> namespace is already <%s>.", ctxName, to)
+		return
+	}
+
+	current, _ := currentContext(config)
+	if file := session.Current(); len(file) > 0 && ctxName == current {
+		writeSession(file, config, ctxName, to)
+		output.Done("Switched to namespace <%s> of context <%s> in this shell.", to, ctxName)
+	} else {
+		config.Contexts[ctxName].Namespace = to
+		kube.SaveConfigToFile(config, rootFlag.kubeconfig)
+		output.Done("Switched to namespace <%s> of context <%s>.", to, ctxName)
+	}
+
+	// 只记录当前 context 的切换，"ktx -" 才能按预期返回
+	if ctxName == current {
+		recordSwitch(ctxName, from, ctxName, to)
+	}
+}
+
+// contextNamespaces is the namespaces of a context, or the error listing them.
+type contextNamespaces struct {
+	context    string
+	namespaces []corev1.Namespace
+	err        error
+}
+
+// listContextsNamespaces lists the namespaces of the selected contexts, and
+// offers to switch to one of them if in a terminal.
+func listContextsNamespaces(config *clientcmdapi.Config) {
+	var filter *regexp.Regexp
+	if len(nsFlag.filter) > 0 {
+		var err error
+		if filter, err = regexp.Compile(nsFlag.filter); err != nil {
+			output.Fatal("Invalid filter <%s>: %s", nsFlag.filter, err)
+		}
+	}
+
+	dsts := selectContexts(config, nsFlag.allContexts, nsFlag.contexts, nsFlag.selector)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	results := make([]*contextNamespaces, len(dsts))
+	for i, dst := range dsts {
+		results[i] = &contextNamespaces{context: dst, err: context.Canceled}
+	}
+	util.Parallel(ctx, results, nsFlag.parallel, func(ctx context.Context, result *contextNamespaces) {
+		result.namespaces, result.err = kube.ListContextNamespaceObjects(ctx, rootFlag.kubeconfig, result.context, nsFlag.timeout)
+	})
+
+	var (
+		failed  bool
+		pairs   []string
+		targets [][2]string
+		now     = time.Now()
+		t       = table.NewWriter()
+	)
+	t.AppendHeader(table.Row{"context", "namespace", "status", "age", "labels"})
+	for _, result := range results {
+		if result.err != nil {
+			failed = true
+			t.AppendRow(table.Row{result.context, kube.ClassifyError(result.err).ColorString(), "-", "-", color.New(color.Faint).Sprint(result.err)})
+			continue
+		}
+		sort.Slice(result.namespaces, func(i, j int) bool {
+			return result.namespaces[i].Name < result.namespaces[j].Name
+		})
+		for _, ns := range result.namespaces {
+			if filter != nil && !filter.MatchString(ns.Name) {
+				continue
+			}
+			pairs = append(pairs, result.context+"/"+ns.Name)
+			targets = append(targets, [2]string{result.context, ns.Name})
+			t.AppendRow(table.Row{
+				result.context,
+				ns.Name,
+				namespacePhase(ns.Status.Phase),
+				util.If(ns.CreationTimestamp.IsZero(), "-", util.HumanDuration(now.Sub(ns.CreationTimestamp.Time))),
+				formatNamespaceLabels(ns.Labels),
+			})
+		}
+	}
+	t.SetStyle(tableStyle)
+	fmt.Println(t.Render())
+
+	if len(pairs) == 0 {
+		output.Note("No namespace matched.")
+	} else if term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd())) {
+		// 选择后直接切换到对应的 context 和 namespace，失败的 context 仍以非零退出码退出
+		if pair, ok := prompt.Selection("Switch to context/namespace", pairs, ""); ok {
+			dst := targets[slices.Index(pairs, pair)]
+			switchContext(config, dst[0], dst[1])
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func namespacePhase(phase corev1.NamespacePhase) string {
+	switch phase {
+	case corev1.NamespaceActive:
+		return color.GreenString(string(phase))
+	case corev1.NamespaceTerminating:
+		return color.YellowString(string(phase))
+	default:
+		return util.If(len(phase) == 0, "-", string(phase))
+	}
+}
+
+// formatNamespaceLabels formats the labels as sorted key=values, without the
+// name label set on every namespace.
+func formatNamespaceLabels(labels map[string]string) string {
+	var pairs []string
+	for k, v := range labels {
+		if k == corev1.LabelMetadataName {
+			continue
+		}
+		pairs = append(pairs, k+"="+v)
+	}
+	if len(pairs) == 0 {
+		return "-"
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
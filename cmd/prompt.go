@@ -0,0 +1,171 @@
+/*
+Copyright © 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/ketches/ktx/internal/completion"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/session"
+	"github.com/ketches/ktx/internal/state"
+	"github.com/ketches/ktx/internal/util"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+type promptFlags struct {
+	format  string
+	shell   string
+	noColor bool
+}
+
+var promptFlag promptFlags
+
+// promptCmd represents the prompt command
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Print the current context and namespace for the shell prompt",
+	Long: `Print the current context and namespace for the shell prompt, colored by the env label of the context.
+
+Only the kubeconfig kubectl would use is read, no request is sent to the
+cluster. Nothing is printed if there is no current context. Available fields
+of --format: .Context, .Namespace, .Cluster, .User, .Server and .Env.
+
+Use --shell bash or zsh when embedding in PS1 or RPROMPT, so that the color
+sequences are not counted in the prompt width. See "ktx init".`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runPrompt(cmd)
+	},
+	ValidArgsFunction: completion.None,
+}
+
+func init() {
+	rootCmd.AddCommand(promptCmd)
+
+	promptCmd.Flags().StringVar(&promptFlag.format, "format", "⎈ {{.Context}}:{{.Namespace}}", "Go template of the prompt segment")
+	promptCmd.Flags().StringVar(&promptFlag.shell, "shell", "", "Shell the segment is embedded in, one of: bash, zsh")
+	promptCmd.Flags().BoolVar(&promptFlag.noColor, "no-color", false, "Print without color")
+}
+
+type promptData struct {
+	Context   string
+	Namespace string
+	Cluster   string
+	User      string
+	Server    string
+	Env       string
+}
+
+func runPrompt(cmd *cobra.Command) {
+	tmpl, err := template.New("prompt").Parse(promptFlag.format)
+	if err != nil {
+		// 提示符中不输出错误，避免污染终端
+		return
+	}
+
+	config := promptConfig(cmd)
+	if config == nil || len(config.CurrentContext) == 0 {
+		return
+	}
+	ctx, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return
+	}
+
+	data := promptData{
+		Context:   config.CurrentContext,
+		Namespace: util.If(len(ctx.Namespace) > 0, ctx.Namespace, kube.DefaultNamespace),
+		Cluster:   ctx.Cluster,
+		User:      ctx.AuthInfo,
+	}
+	if cluster, ok := config.Clusters[ctx.Cluster]; ok {
+		data.Server = cluster.Server
+	}
+	if s, err := state.Load(state.DefaultFile); err == nil {
+		if meta, ok := s.Contexts[data.Context]; ok {
+			data.Env = meta.Labels["env"]
+		}
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return
+	}
+	fmt.Print(promptSegment(b.String(), data.Env, promptFlag.shell, !promptFlag.noColor))
+}
+
+// promptConfig loads the kubeconfig kubectl would use: the ktx session of the
+// shell, --kubeconfig if specified, otherwise $KUBECONFIG or ~/.kube/config.
+func promptConfig(cmd *cobra.Command) *clientcmdapi.Config {
+	if file := session.Current(); len(file) > 0 && !cmd.Flags().Changed("kubeconfig") {
+		config, err := clientcmd.LoadFromFile(file)
+		if err != nil {
+			return nil
+		}
+		return config
+	}
+
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if cmd.Flags().Changed("kubeconfig") {
+		rules.ExplicitPath = rootFlag.kubeconfig
+	}
+	config, err := rules.Load()
+	if err != nil {
+		return nil
+	}
+	return config
+}
+
+// promptSegment colors the text by the environment, wrapping the color
+// sequences as zero width for the shell.
+func promptSegment(text, env, shell string, colored bool) string {
+	if shell == "zsh" {
+		text = strings.ReplaceAll(text, "%", "%%")
+	}
+	if !colored {
+		return text
+	}
+
+	start, end := "\033["+envColorCode(env)+"m", "\033[0m"
+	switch shell {
+	case "bash":
+		start, end = "\001"+start+"\002", "\001"+end+"\002"
+	case "zsh":
+		start, end = "%{"+start+"%}", "%{"+end+"%}"
+	}
+	return start + text + end
+}
+
+// envColorCode returns the SGR color code of the env label, red for
+// production, yellow for staging and green for development.
+func envColorCode(env string) string {
+	switch strings.ToLower(env) {
+	case "prod", "production", "prd", "live":
+		return "1;31"
+	case "staging", "stage", "stg", "uat", "pre", "preprod":
+		return "33"
+	case "dev", "development", "test", "testing", "qa", "local":
+		return "32"
+	default:
+		return "36"
+	}
+}
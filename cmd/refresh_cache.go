@@ -0,0 +1,52 @@
+/*
+Copyright © 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/ketches/ktx/internal/lookup"
+	"github.com/spf13/cobra"
+)
+
+// refreshCacheCmd represents the hidden command refreshing the completion
+// cache in the background
+var refreshCacheCmd = &cobra.Command{
+	Use:    lookup.RefreshCommand + " <kind> <context> [namespace]",
+	Short:  "Refresh the completion cache",
+	Hidden: true,
+	Args:   cobra.RangeArgs(2, 3),
+	Run: func(cmd *cobra.Command, args []string) {
+		runRefreshCache(args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(refreshCacheCmd)
+}
+
+func runRefreshCache(args []string) {
+	var namespace string
+	if len(args) > 2 {
+		namespace = args[2]
+	}
+	// 后台运行，没有终端，只通过退出码反映结果
+	if err := lookup.Refresh(context.Background(), rootFlag.kubeconfig, args[0], args[1], namespace); err != nil {
+		os.Exit(1)
+	}
+}
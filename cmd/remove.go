@@ -76,6 +76,10 @@ func removeContext(config *api.Config, dst string) {
 	}
 
 	kube.SaveConfigToFile(config, rootFlag.kubeconfig)
+
+	s := loadState()
+	s.Remove(dst)
+	saveState(s)
 	output.Done("Context <%s> removed.", dst)
 
 	// 如果当前没有 context，那么提示用户选择一个 context
@@ -97,5 +97,9 @@ func renameContext(oldCtxName string, config *clientcmdapi.Config) {
 	delete(config.Contexts, oldCtxName)
 
 	kube.SaveConfigToFile(config, rootFlag.kubeconfig)
+
+	s := loadState()
+	s.Rename(oldCtxName, newCtxName)
+	saveState(s)
 	output.Done("Context <%s> renamed to <%s>.", oldCtxName, newCtxName)
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package cmd
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/ketches/ktx/internal/kube"
@@ -34,6 +35,13 @@ var rootCmd = &cobra.Command{
 	Use:   "ktx",
 	Short: "ktx is a tool to manage kubernetes contexts.",
 	Long:  `ktx is a tool to manage kubernetes contexts.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		// 只接受 "-"，其他参数视为未知子命令
+		if len(args) == 0 || (len(args) == 1 && args[0] == "-") {
+			return nil
+		}
+		return fmt.Errorf("unknown command %q for %q", args[0], cmd.CommandPath())
+	},
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	Run: func(cmd *cobra.Command, args []string) {
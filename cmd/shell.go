@@ -0,0 +1,114 @@
+/*
+Copyright © 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/ketches/ktx/internal/completion"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/output"
+	"github.com/ketches/ktx/internal/prompt"
+	"github.com/ketches/ktx/internal/session"
+	"github.com/spf13/cobra"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+type shellFlags struct {
+	namespace string
+}
+
+var shellFlag shellFlags
+
+// shellCmd represents the shell command
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Start a subshell isolated to a context",
+	Long: `Start a subshell with KUBECONFIG pointing at a session kubeconfig holding only the context.
+
+Switching contexts in the subshell with ktx changes the session only, other
+terminals keep their context. The session kubeconfig is removed when the
+subshell exits.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runShell(args)
+	},
+	ValidArgsFunction: completion.Context,
+}
+
+func init() {
+	rootCmd.AddCommand(shellCmd)
+
+	shellCmd.Flags().StringVarP(&shellFlag.namespace, "namespace", "n", "", "Namespace of the session, the namespace of the context by default")
+}
+
+func runShell(args []string) {
+	config := kube.LoadConfigFromFile(rootFlag.kubeconfig)
+
+	var dst string
+	if len(args) == 0 {
+		dst = prompt.ContextSelection("Select context for the shell", config)
+	} else {
+		dst = args[0]
+	}
+
+	file := newSession(config, dst, shellFlag.namespace)
+	defer os.Remove(file)
+
+	shell := os.Getenv("SHELL")
+	if runtime.GOOS == "windows" {
+		shell = os.Getenv("COMSPEC")
+	}
+	if len(shell) == 0 {
+		shell = "/bin/sh"
+	}
+
+	c := exec.Command(shell)
+	c.Env = append(os.Environ(), "KUBECONFIG="+file, session.Env+"="+file)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	output.Note("Entering shell of context <%s>, exit to return.", dst)
+	err := c.Run()
+	os.Remove(file)
+	output.Note("Left shell of context <%s>.", dst)
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.ExitCode())
+	} else if err != nil {
+		output.Fatal("Failed to start shell %s: %s", shell, err)
+	}
+}
+
+// newSession creates a session kubeconfig holding only the context.
+func newSession(config *clientcmdapi.Config, dst, namespace string) string {
+	file, err := session.Create()
+	if err != nil {
+		output.Fatal("Failed to create session: %s", err)
+	}
+	writeSession(file, config, dst, namespace)
+	return file
+}
+
+func writeSession(file string, config *clientcmdapi.Config, dst, namespace string) {
+	if err := session.Write(file, kube.MinifyConfig(config, dst, namespace)); err != nil {
+		output.Fatal("Failed to write session: %s", err)
+	}
+}
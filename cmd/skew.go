@@ -0,0 +1,289 @@
+/*
+Copyright © 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/ketches/ktx/internal/completion"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/kubectl"
+	"github.com/ketches/ktx/internal/output"
+	"github.com/ketches/ktx/internal/util"
+	"github.com/spf13/cobra"
+)
+
+type skewFlags struct {
+	all      bool
+	selector string
+	kubectl  string
+	output   string
+	timeout  time.Duration
+	parallel int
+}
+
+var skewFlag skewFlags
+
+// skewCmd represents the skew command
+var skewCmd = &cobra.Command{
+	Use:   "skew [context...]",
+	Short: "Check the version skew between kubectl and clusters",
+	Long: `Check the version skew between the local kubectl, the client-go built into
+ktx, and the server version of the current context, or the contexts specified.
+The kubectl checked is the binary "ktx kubectl" resolves for each context,
+unless --kubectl is specified.
+
+By the Kubernetes version skew policy, kubectl is supported within one minor
+version (older or newer) of kube-apiserver. The skew of client-go is checked
+against the same window for information only. Exits with code 1 if kubectl is
+outside the supported skew of any context, or a context is unreachable.
+
+Set KTX_CHECK_SKEW=true or pass --check-skew to "ktx switch" to be warned when
+switching to a cluster outside the supported skew of kubectl.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runSkew(args)
+	},
+	ValidArgsFunction: completion.ContextArray,
+}
+
+func init() {
+	rootCmd.AddCommand(skewCmd)
+
+	skewCmd.Flags().BoolVarP(&skewFlag.all, "all", "A", false, "Check all contexts")
+	skewCmd.Flags().StringVarP(&skewFlag.selector, "selector", "l", "", "Check contexts matching the label selector, eg. env=prod")
+	skewCmd.Flags().StringVar(&skewFlag.kubectl, "kubectl", "", "Path of the kubectl binary, the binary resolved for each context by default")
+	skewCmd.Flags().StringVarP(&skewFlag.output, "output", "o", "", "Output format, one of: json")
+	skewCmd.Flags().DurationVar(&skewFlag.timeout, "timeout", 10*time.Second, "Timeout of requesting the server version")
+	skewCmd.Flags().IntVar(&skewFlag.parallel, "parallel", 16, "Maximum number of contexts requested concurrently")
+}
+
+// skewResult is the skew of kubectl and client-go against the server of a
+// context, or the error getting the server version.
+type skewResult struct {
+	Context     string     `json:"context"`
+	Server      string     `json:"server,omitempty"`
+	KubectlPath string     `json:"kubectlPath,omitempty"`
+	Kubectl     *kube.Skew `json:"kubectl,omitempty"`
+	ClientGo    *kube.Skew `json:"clientGo,omitempty"`
+	// KubectlError is the error resolving kubectl or getting its version,
+	// only client-go is checked then.
+	KubectlError string `json:"kubectlError,omitempty"`
+	Error        string `json:"error,omitempty"`
+	kubectlErr   error
+	err          error
+}
+
+func runSkew(args []string) {
+	if skewFlag.output != "" && skewFlag.output != "json" {
+		output.Fatal("Unsupported output format <%s>.", skewFlag.output)
+	}
+	if skewFlag.output == "json" {
+		output.UseStderr()
+	}
+
+	config := kube.LoadConfigFromFile(rootFlag.kubeconfig)
+	dsts := selectContexts(config, skewFlag.all, args, skewFlag.selector)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// 未指定 --kubectl 时按 context 解析 kubectl
+	versions := &kubectlVersions{}
+	kubectlPath := skewFlag.kubectl
+	if len(kubectlPath) > 0 {
+		if _, err := versions.get(ctx, kubectlPath); err != nil {
+			output.Fatal("Failed to get the version of kubectl: %s", err)
+		}
+	}
+	clientGoVersion := kube.ClientGoVersion()
+
+	results := make([]*skewResult, len(dsts))
+	for i, dst := range dsts {
+		results[i] = &skewResult{Context: dst, err: context.Canceled}
+	}
+	util.Parallel(ctx, results, skewFlag.parallel, func(ctx context.Context, result *skewResult) {
+		probe := kube.Probe(ctx, rootFlag.kubeconfig, result.Context, skewFlag.timeout)
+		result.Server, result.err = probe.Version, probe.Err
+		if result.err != nil {
+			return
+		}
+		result.KubectlPath = kubectlPath
+		if len(result.KubectlPath) == 0 {
+			resolution, err := resolveKubectl(ctx, result.Context, func() string { return probe.Version })
+			if err != nil {
+				result.kubectlErr = err
+			} else {
+				result.KubectlPath = resolution.Path
+			}
+		}
+		if len(result.KubectlPath) > 0 {
+			var kubectlVersion string
+			if kubectlVersion, result.kubectlErr = versions.get(ctx, result.KubectlPath); result.kubectlErr == nil {
+				result.Kubectl, result.err = kube.CheckSkew(kubectlVersion, result.Server)
+			}
+		}
+		if len(clientGoVersion) > 0 && result.err == nil {
+			result.ClientGo, result.err = kube.CheckSkew(clientGoVersion, result.Server)
+		}
+	})
+
+	var failed bool
+	for _, result := range results {
+		if result.err != nil {
+			result.Error = result.err.Error()
+		}
+		if result.kubectlErr != nil {
+			result.KubectlError = result.kubectlErr.Error()
+		}
+		if result.err != nil || result.Kubectl != nil && !result.Kubectl.Supported {
+			failed = true
+		}
+	}
+
+	if skewFlag.output == "json" {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			output.Fatal("Failed to marshal version skews: %s", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		output.Note("client-go %s", util.If(len(clientGoVersion) > 0, clientGoVersion, "unknown"))
+		printSkews(results, versions)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func printSkews(results []*skewResult, versions *kubectlVersions) {
+	// client-go 超出范围只作提示
+	skew := func(s *kube.Skew, advisory bool) string {
+		switch {
+		case s == nil:
+			return "-"
+		case s.Supported:
+			return color.GreenString("✓ %s", s)
+		case advisory:
+			return color.YellowString("! %s", s)
+		default:
+			return color.RedString("✗ %s", s)
+		}
+	}
+	binary := func(result *skewResult) string {
+		switch {
+		case result.kubectlErr != nil && errors.Is(result.kubectlErr, exec.ErrNotFound):
+			return color.New(color.Faint).Sprint("not found")
+		case result.kubectlErr != nil:
+			return color.New(color.Faint).Sprint(result.KubectlError)
+		case len(result.KubectlPath) == 0:
+			return "-"
+		}
+		version, _ := versions.get(context.Background(), result.KubectlPath)
+		return fmt.Sprintf("%s %s", result.KubectlPath, color.CyanString(version))
+	}
+
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"context", "server", "kubectl binary", "kubectl", "client-go"})
+	for _, result := range results {
+		if result.err != nil && len(result.Server) == 0 {
+			t.AppendRow(table.Row{result.Context, kube.ClassifyError(result.err).ColorString(), "-", "-", color.New(color.Faint).Sprint(result.Error)})
+			continue
+		}
+		if result.err != nil {
+			t.AppendRow(table.Row{result.Context, result.Server, binary(result), "-", color.New(color.Faint).Sprint(result.Error)})
+			continue
+		}
+		t.AppendRow(table.Row{result.Context, color.CyanString(result.Server), binary(result), skew(result.Kubectl, false), skew(result.ClientGo, true)})
+	}
+	t.SetStyle(tableStyle)
+	fmt.Println(t.Render())
+}
+
+// kubectlVersions gets the versions of kubectl binaries, each binary is run
+// once.
+type kubectlVersions struct {
+	mu       sync.Mutex
+	versions map[string]*kubectlVersion
+}
+
+type kubectlVersion struct {
+	once    sync.Once
+	version string
+	err     error
+}
+
+func (v *kubectlVersions) get(ctx context.Context, path string) (string, error) {
+	v.mu.Lock()
+	if v.versions == nil {
+		v.versions = make(map[string]*kubectlVersion)
+	}
+	entry, ok := v.versions[path]
+	if !ok {
+		entry = &kubectlVersion{}
+		v.versions[path] = entry
+	}
+	v.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.version, entry.err = kubectl.Version(ctx, path)
+	})
+	return entry.version, entry.err
+}
+
+// checkSkewFromEnv returns whether to check the version skew on switching by
+// default, set by KTX_CHECK_SKEW.
+func checkSkewFromEnv() bool {
+	check, _ := strconv.ParseBool(os.Getenv("KTX_CHECK_SKEW"))
+	return check
+}
+
+// warnSkew warns if the kubectl resolved for the context is outside the
+// supported skew of the server of the context. Nothing is warned if kubectl or
+// the server is unavailable, switching is done already.
+func warnSkew(contextName string) {
+	ctx := context.Background()
+	probe := kube.Probe(ctx, rootFlag.kubeconfig, contextName, kube.DefaultProbeTimeout)
+	if probe.Err != nil {
+		return
+	}
+	resolution, err := resolveKubectl(ctx, contextName, func() string { return probe.Version })
+	if err != nil {
+		return
+	}
+	kubectlVersion, err := kubectl.Version(ctx, resolution.Path)
+	if err != nil {
+		return
+	}
+	skew, err := kube.CheckSkew(kubectlVersion, probe.Version)
+	if err != nil || skew.Supported {
+		return
+	}
+	output.Warn("kubectl %s (%s) is outside the supported skew of the server %s of context <%s>: %s.",
+		kubectlVersion, resolution.Path, probe.Version, contextName, skew)
+}
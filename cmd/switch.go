@@ -17,21 +17,39 @@ limitations under the License.
 package cmd
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/ketches/ktx/internal/completion"
 	"github.com/ketches/ktx/internal/kube"
 	"github.com/ketches/ktx/internal/output"
 	"github.com/ketches/ktx/internal/prompt"
+	"github.com/ketches/ktx/internal/session"
+	"github.com/ketches/ktx/internal/state"
+	"github.com/ketches/ktx/internal/util"
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
 
+type switchFlags struct {
+	selectNamespace bool
+	checkSkew       bool
+}
+
+var switchFlag switchFlags
+
 // switchCmd represents the switch command
 var switchCmd = &cobra.Command{
-	Use:     "switch",
+	Use:     "switch [context[/namespace]|-]",
 	Aliases: []string{"s"},
 	Short:   "Switch context in specified kubeconfig(~/.kube/config by default)",
-	Long:    `Switch context in specified kubeconfig(~/.kube/config by default)`,
-	Args:    cobra.MaximumNArgs(1),
+	Long: `Switch context in specified kubeconfig(~/.kube/config by default)
+
+Switch the context and set its namespace at once with "context/namespace" or
+"context:namespace". Use "ktx switch -" or "ktx -" to switch back to the
+previous context and namespace.`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		runSwitch(args)
 	},
@@ -40,6 +58,12 @@ var switchCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(switchCmd)
+
+	// 根命令默认执行 switch，共享参数
+	for _, cmd := range []*cobra.Command{rootCmd, switchCmd} {
+		cmd.Flags().BoolVarP(&switchFlag.selectNamespace, "select-namespace", "N", false, "Select the namespace after the context")
+		cmd.Flags().BoolVar(&switchFlag.checkSkew, "check-skew", checkSkewFromEnv(), "Warn if kubectl is outside the supported version skew of the cluster, $KTX_CHECK_SKEW by default")
+	}
 }
 
 func runSwitch(args []string) {
@@ -52,16 +76,96 @@ func runSwitch(args []string) {
 		dst = args[0]
 	}
 
-	switchContext(config, dst)
+	// "-" 切换回上一个 context 和 namespace
+	if dst == "-" {
+		current, namespace := currentContext(config)
+		prev := loadState().Previous(current, namespace)
+		if prev == nil {
+			output.Fatal("No previous context to switch back to.")
+		}
+		switchContext(config, prev.Context, prev.Namespace)
+		if switchFlag.checkSkew {
+			warnSkew(prev.Context)
+		}
+		return
+	}
+
+	dst, namespace := kube.ParseContextNamespace(config, dst)
+	if _, ok := config.Contexts[dst]; !ok {
+		output.Fatal("Context <%s> not found.", dst)
+	}
+	if len(namespace) > 0 {
+		checkNamespace(dst, namespace)
+	} else if switchFlag.selectNamespace {
+		ctx := config.Contexts[dst]
+		namespace = selectNamespace("Select namespace of context <"+dst+">", dst,
+			util.If(len(ctx.Namespace) > 0, ctx.Namespace, kube.DefaultNamespace))
+	}
+
+	switchContext(config, dst, namespace)
+	if switchFlag.checkSkew {
+		warnSkew(dst)
+	}
 }
 
-func switchContext(config *clientcmdapi.Config, dst string) {
-	_, ok := config.Contexts[dst]
+// switchContext switches to the context, and sets its namespace if specified.
+func switchContext(config *clientcmdapi.Config, dst, namespace string) {
+	ctx, ok := config.Contexts[dst]
 	if !ok {
 		output.Fatal("Context <%s> not found.", dst)
 	}
+	fromContext, fromNamespace := currentContext(config)
 
-	config.CurrentContext = dst
-	kube.SaveConfigToFile(config, rootFlag.kubeconfig)
-	output.Done("Switched to context <%s>.", dst)
+	target := fmt.Sprintf("context <%s>", dst)
+	if len(namespace) > 0 {
+		target = fmt.Sprintf("context <%s> namespace <%s>", dst, namespace)
+	} else {
+		namespace = util.If(len(ctx.Namespace) > 0, ctx.Namespace, kube.DefaultNamespace)
+	}
+
+	// 在 ktx 会话中只切换会话的 context，不影响其他终端
+	if file := session.Current(); len(file) > 0 {
+		writeSession(file, config, dst, namespace)
+		output.Done("Switched to %s in this shell.", target)
+	} else {
+		config.CurrentContext = dst
+		if namespace != util.If(len(ctx.Namespace) > 0, ctx.Namespace, kube.DefaultNamespace) {
+			ctx.Namespace = namespace
+		}
+		kube.SaveConfigToFile(config, rootFlag.kubeconfig)
+		output.Done("Switched to %s.", target)
+	}
+
+	recordSwitch(fromContext, fromNamespace, dst, namespace)
+	warnExpiry(config, dst)
+}
+
+// currentContext returns the current context and namespace, of the session if
+// the shell is in one.
+func currentContext(config *clientcmdapi.Config) (string, string) {
+	if file := session.Current(); len(file) > 0 {
+		if c, err := clientcmd.LoadFromFile(file); err == nil {
+			config = c
+		}
+	}
+	ctx, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return config.CurrentContext, ""
+	}
+	return config.CurrentContext, util.If(len(ctx.Namespace) > 0, ctx.Namespace, kube.DefaultNamespace)
+}
+
+// recordSwitch records the switch in the history, the switch is done already
+// so failures are only warned.
+func recordSwitch(fromContext, fromNamespace, toContext, toNamespace string) {
+	s, err := state.Load(state.DefaultFile)
+	if err == nil {
+		now := time.Now()
+		s.Record(state.Switch{Context: fromContext, Namespace: fromNamespace, Time: now},
+			state.Switch{Context: toContext, Namespace: toNamespace, Time: now})
+		err = s.Save()
+	}
+	if err != nil {
+		output.Warn("Failed to record switch history: %s", err)
+	}
 }
@@ -0,0 +1,212 @@
+/*
+Copyright © 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/ketches/ktx/internal/completion"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/output"
+	"github.com/ketches/ktx/internal/prompt"
+	"github.com/ketches/ktx/internal/vault"
+	"github.com/spf13/cobra"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// vaultCmd represents the vault command
+var vaultCmd = &cobra.Command{
+	Use:   "vault",
+	Short: "Manage credentials in the ktx encrypted vault",
+	Long: `Manage credentials in the ktx encrypted vault.
+
+Sealed contexts keep their token or client certificate in an encrypted vault
+(~/.kube/ktx/vault) instead of the kubeconfig, and authenticate through the
+"ktx credential" exec plugin. The vault passphrase is read from the
+KTX_VAULT_PASSPHRASE environment variable, or prompted for.`,
+	ValidArgsFunction: completion.None,
+}
+
+// vaultSealCmd represents the vault seal command
+var vaultSealCmd = &cobra.Command{
+	Use:   "seal",
+	Short: "Move context credentials into the vault",
+	Long:  `Move context token or client certificate into the vault, and replace the user with an exec stanza calling ktx.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runVaultSeal(args)
+	},
+	ValidArgsFunction: completion.ContextArray,
+}
+
+// vaultUnsealCmd represents the vault unseal command
+var vaultUnsealCmd = &cobra.Command{
+	Use:   "unseal",
+	Short: "Move context credentials back from the vault",
+	Long:  `Move context token or client certificate back from the vault into the kubeconfig.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runVaultUnseal(args)
+	},
+	ValidArgsFunction: completion.ContextArray,
+}
+
+func init() {
+	rootCmd.AddCommand(vaultCmd)
+	vaultCmd.AddCommand(vaultSealCmd)
+	vaultCmd.AddCommand(vaultUnsealCmd)
+}
+
+func runVaultSeal(args []string) {
+	config := kube.LoadConfigFromFile(rootFlag.kubeconfig)
+
+	dsts := args
+	if len(dsts) == 0 {
+		dsts = []string{prompt.ContextSelection("Select context to seal", config)}
+	}
+
+	v := openVault()
+	for _, dst := range dsts {
+		sealContext(config, v, dst)
+	}
+}
+
+func runVaultUnseal(args []string) {
+	config := kube.LoadConfigFromFile(rootFlag.kubeconfig)
+
+	dsts := args
+	if len(dsts) == 0 {
+		dsts = []string{prompt.ContextSelection("Select context to unseal", config)}
+	}
+
+	v := openVault()
+	for _, dst := range dsts {
+		unsealContext(config, v, dst)
+	}
+}
+
+func sealContext(config *clientcmdapi.Config, v *vault.Vault, dst string) {
+	user := contextUser(config, dst)
+	if _, ok := kube.IsKtxExec(user.Exec, "credential"); ok {
+		output.Note("Context <%s> already sealed, skipped.", dst)
+		return
+	}
+
+	entry := &vault.Entry{
+		Token:                 user.Token,
+		ClientCertificateData: user.ClientCertificateData,
+		ClientKeyData:         user.ClientKeyData,
+	}
+	if len(entry.Token) == 0 && len(user.TokenFile) > 0 {
+		entry.Token = string(readCredentialFile(user.TokenFile))
+	}
+	if len(entry.ClientCertificateData) == 0 && len(user.ClientCertificate) > 0 {
+		entry.ClientCertificateData = readCredentialFile(user.ClientCertificate)
+	}
+	if len(entry.ClientKeyData) == 0 && len(user.ClientKey) > 0 {
+		entry.ClientKeyData = readCredentialFile(user.ClientKey)
+	}
+
+	if len(entry.Token) == 0 && (len(entry.ClientCertificateData) == 0 || len(entry.ClientKeyData) == 0) {
+		output.Fatal("Context <%s> has no token or client certificate to seal.", dst)
+	}
+
+	v.Entries[dst] = entry
+	if err := v.Save(); err != nil {
+		output.Fatal("Failed to save vault: %s", err)
+	}
+
+	user.Token = ""
+	user.TokenFile = ""
+	user.ClientCertificate = ""
+	user.ClientCertificateData = nil
+	user.ClientKey = ""
+	user.ClientKeyData = nil
+	user.Exec = kube.KtxExecConfig("credential", dst)
+
+	kube.SaveConfigToFile(config, rootFlag.kubeconfig)
+	output.Done("Context <%s> sealed.", dst)
+}
+
+func unsealContext(config *clientcmdapi.Config, v *vault.Vault, dst string) {
+	user := contextUser(config, dst)
+	args, ok := kube.IsKtxExec(user.Exec, "credential")
+	if !ok || len(args) == 0 {
+		output.Note("Context <%s> is not sealed, skipped.", dst)
+		return
+	}
+
+	key := args[0]
+	entry, ok := v.Entries[key]
+	if !ok {
+		output.Fatal("Credential <%s> not found in vault.", key)
+	}
+	if entry.OIDC != nil {
+		output.Note("Context <%s> uses an OIDC login which can not be unsealed, skipped.", dst)
+		return
+	}
+
+	user.Exec = nil
+	user.Token = entry.Token
+	user.ClientCertificateData = entry.ClientCertificateData
+	user.ClientKeyData = entry.ClientKeyData
+	kube.SaveConfigToFile(config, rootFlag.kubeconfig)
+
+	delete(v.Entries, key)
+	if err := v.Save(); err != nil {
+		output.Fatal("Failed to save vault: %s", err)
+	}
+	output.Done("Context <%s> unsealed.", dst)
+}
+
+// contextUser returns the user of the context, and exits if not found.
+func contextUser(config *clientcmdapi.Config, ctxName string) *clientcmdapi.AuthInfo {
+	ctx, ok := config.Contexts[ctxName]
+	if !ok {
+		output.Fatal("Context <%s> not found.", ctxName)
+	}
+
+	user, ok := config.AuthInfos[ctx.AuthInfo]
+	if !ok {
+		output.Fatal("User not found for context <%s>.", ctxName)
+	}
+	return user
+}
+
+func readCredentialFile(file string) []byte {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		output.Fatal("Failed to read %s: %s", file, err)
+	}
+	return data
+}
+
+// openVault unlocks the vault with the passphrase from the environment, or
+// prompts for it.
+func openVault() *vault.Vault {
+	passphrase := os.Getenv(vault.PassphraseEnv)
+	if len(passphrase) == 0 {
+		passphrase = prompt.Password("Enter vault passphrase")
+		if !vault.Exist(vault.DefaultFile) && prompt.Password("Confirm vault passphrase") != passphrase {
+			output.Fatal("Passphrases do not match.")
+		}
+	}
+
+	v, err := vault.Open(vault.DefaultFile, []byte(passphrase))
+	if err != nil {
+		output.Fatal("Failed to open vault: %s", err)
+	}
+	return v
+}
@@ -0,0 +1,188 @@
+/*
+Copyright © 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/ketches/ktx/internal/completion"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/output"
+	"github.com/ketches/ktx/internal/util"
+	"github.com/spf13/cobra"
+)
+
+type whoamiFlags struct {
+	all      bool
+	output   string
+	timeout  time.Duration
+	parallel int
+}
+
+var whoamiFlag whoamiFlags
+
+// whoamiCmd represents the whoami command
+var whoamiCmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Show the identity a context authenticates as",
+	Long: `Show the identity a context authenticates as, the current context by default.
+
+The identity is requested with SelfSubjectReview (authentication.k8s.io/v1), and
+decoded locally from the token or client certificate on servers older than 1.27.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runWhoami(args)
+	},
+	ValidArgsFunction: completion.Context,
+}
+
+func init() {
+	rootCmd.AddCommand(whoamiCmd)
+
+	whoamiCmd.Flags().BoolVarP(&whoamiFlag.all, "all", "A", false, "Show identities of all contexts")
+	whoamiCmd.Flags().StringVarP(&whoamiFlag.output, "output", "o", "", "Output format, one of: json")
+	whoamiCmd.Flags().DurationVar(&whoamiFlag.timeout, "timeout", 10*time.Second, "Timeout of each request")
+	whoamiCmd.Flags().IntVar(&whoamiFlag.parallel, "parallel", 16, "Maximum number of contexts requested concurrently")
+}
+
+// whoamiResult is the identity of a context, or the error getting it.
+type whoamiResult struct {
+	*kube.Identity
+	Context string `json:"context"`
+	Error   string `json:"error,omitempty"`
+	err     error
+}
+
+func runWhoami(args []string) {
+	if whoamiFlag.output != "" && whoamiFlag.output != "json" {
+		output.Fatal("Unsupported output format <%s>.", whoamiFlag.output)
+	}
+
+	config := kube.LoadConfigFromFile(rootFlag.kubeconfig)
+
+	dsts := selectContexts(config, whoamiFlag.all, args, "")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	results := make([]*whoamiResult, len(dsts))
+	for i, dst := range dsts {
+		results[i] = &whoamiResult{Context: dst}
+	}
+	util.Parallel(ctx, results, whoamiFlag.parallel, func(ctx context.Context, result *whoamiResult) {
+		identity, err := kube.WhoAmI(ctx, rootFlag.kubeconfig, result.Context, whoamiFlag.timeout)
+		if err != nil {
+			result.err = err
+			result.Error = err.Error()
+			return
+		}
+		result.Identity = identity
+	})
+
+	var failed bool
+	for _, result := range results {
+		if result.Identity == nil {
+			failed = true
+			if result.err == nil {
+				result.err = context.Canceled
+				result.Error = result.err.Error()
+			}
+		}
+	}
+
+	if whoamiFlag.output == "json" {
+		var v any = results
+		if !whoamiFlag.all && len(results) == 1 {
+			v = results[0]
+		}
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			output.Fatal("Failed to marshal identities: %s", err)
+		}
+		fmt.Println(string(data))
+	} else if !whoamiFlag.all && len(results) == 1 {
+		printIdentity(results[0])
+	} else {
+		printIdentities(results)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func printIdentity(result *whoamiResult) {
+	if result.Identity == nil {
+		output.Fatal("Failed to get identity of context <%s>: %s", result.Context, result.Error)
+	}
+
+	field := func(name, value string) {
+		fmt.Printf("%s %s\n", color.New(color.Faint).Sprintf("%-9s", name+":"), value)
+	}
+	field("Context", result.Context)
+	field("Username", color.CyanString(result.Username))
+	if len(result.UID) > 0 {
+		field("UID", result.UID)
+	}
+	if len(result.Groups) > 0 {
+		field("Groups", strings.Join(result.Groups, ", "))
+	}
+	for _, extra := range formatExtra(result.Extra) {
+		field("Extra", extra)
+	}
+	field("Source", result.Source)
+}
+
+func printIdentities(results []*whoamiResult) {
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"context", "username", "uid", "groups", "extra", "source"})
+	for _, result := range results {
+		if result.Identity == nil {
+			t.AppendRow(table.Row{result.Context, kube.ClassifyError(result.err).ColorString(), "-", "-", "-", color.New(color.Faint).Sprint(result.Error)})
+			continue
+		}
+		t.AppendRow(table.Row{
+			result.Context,
+			color.CyanString(result.Username),
+			util.If(result.UID == "", "-", result.UID),
+			util.If(len(result.Groups) == 0, "-", strings.Join(result.Groups, ", ")),
+			util.If(len(result.Extra) == 0, "-", strings.Join(formatExtra(result.Extra), "; ")),
+			result.Source,
+		})
+	}
+	t.SetStyle(tableStyle)
+	fmt.Println(t.Render())
+}
+
+// formatExtra formats the extra of an identity as sorted key=values.
+func formatExtra(extra map[string][]string) []string {
+	var lines []string
+	for k, v := range extra {
+		lines = append(lines, k+"="+strings.Join(v, ","))
+	}
+	sort.Strings(lines)
+	return lines
+}
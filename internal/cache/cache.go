@@ -0,0 +1,120 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/util"
+	"sigs.k8s.io/yaml"
+)
+
+// Dir is the directory of the cached lists.
+var Dir = filepath.Join(kube.DefaultStateDir, "cache")
+
+// Kinds of the cached lists.
+const (
+	KindNamespaces      = "namespaces"
+	KindServiceAccounts = "serviceaccounts"
+	KindServerVersion   = "serverversion"
+	KindKubectl         = "kubectl"
+)
+
+// Entry is a cached list of names, eg. the namespaces of a context.
+type Entry struct {
+	Kind  string    `json:"kind"`
+	Key   string    `json:"key"`
+	Items []string  `json:"items"`
+	Time  time.Time `json:"time"`
+}
+
+// Get returns the cached list of the kind and key, nil if not cached.
+func Get(kind, key string) (*Entry, error) {
+	data, err := os.ReadFile(file(kind, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &Entry{}
+	if err := yaml.Unmarshal(data, entry); err != nil {
+		return nil, err
+	}
+	// 哈希冲突时视为未缓存
+	if entry.Kind != kind || entry.Key != key {
+		return nil, nil
+	}
+	return entry, nil
+}
+
+// Put caches the list of the kind and key.
+func Put(kind, key string, items []string) error {
+	return Save(&Entry{Kind: kind, Key: key, Items: items, Time: time.Now()})
+}
+
+// Save saves the cached list.
+func Save(entry *Entry) error {
+	data, err := yaml.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return util.WriteFile(file(entry.Kind, entry.Key), data)
+}
+
+// Claim claims the refresh of the cached list, false if it is claimed by
+// another process within the period.
+func Claim(kind, key string, period time.Duration) bool {
+	claim := file(kind, key) + ".refresh"
+	if err := os.MkdirAll(filepath.Dir(claim), 0700); err != nil {
+		return false
+	}
+	f, err := os.OpenFile(claim, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err == nil {
+		return f.Close() == nil
+	}
+	if !errors.Is(err, os.ErrExist) {
+		return false
+	}
+
+	// 上次的刷新已过期，重新声明
+	info, err := os.Stat(claim)
+	if err != nil || time.Since(info.ModTime()) < period {
+		return false
+	}
+	now := time.Now()
+	return os.Chtimes(claim, now, now) == nil
+}
+
+// Fresh reports whether the list is cached within the ttl.
+func (e *Entry) Fresh(ttl time.Duration) bool {
+	return time.Since(e.Time) < ttl
+}
+
+// file returns the cache file of the kind and key, the key is hashed since
+// context names may contain path separators.
+func file(kind, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(Dir, kind, hex.EncodeToString(sum[:8])+".yaml")
+}
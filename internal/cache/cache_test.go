@@ -0,0 +1,68 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	Dir = t.TempDir()
+
+	entry, err := Get(KindNamespaces, "prod")
+	if err != nil || entry != nil {
+		t.Fatalf("Get() failed, expected not cached, got: %v, error: %v", entry, err)
+	}
+
+	keys := []string{"prod", "arn:aws:eks:eu-west-1:123456789012:cluster/prod"}
+	for i, key := range keys {
+		if err := Put(KindNamespaces, key, []string{"default", keys[i]}); err != nil {
+			t.Fatalf("Put() failed, error: %s", err)
+		}
+	}
+	for i, key := range keys {
+		entry, err := Get(KindNamespaces, key)
+		if err != nil {
+			t.Fatalf("Get() failed, error: %s", err)
+		}
+		if expected := []string{"default", keys[i]}; entry == nil || !slices.Equal(entry.Items, expected) {
+			t.Errorf("Get() failed, key: %s, expected: %v, got: %v", key, expected, entry)
+		}
+		if entry != nil && entry.Time.IsZero() {
+			t.Errorf("Get() failed, key: %s, expected cached time", key)
+		}
+	}
+}
+
+func TestClaim(t *testing.T) {
+	Dir = t.TempDir()
+
+	if !Claim(KindNamespaces, "prod", time.Minute) {
+		t.Errorf("Claim() failed, expected first claim succeeded")
+	}
+	if Claim(KindNamespaces, "prod", time.Minute) {
+		t.Errorf("Claim() failed, expected claim within the period refused")
+	}
+	if !Claim(KindNamespaces, "dev", time.Minute) {
+		t.Errorf("Claim() failed, expected claim of another key succeeded")
+	}
+	if !Claim(KindNamespaces, "prod", 0) {
+		t.Errorf("Claim() failed, expected expired claim succeeded")
+	}
+}
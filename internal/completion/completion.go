@@ -21,6 +21,7 @@ import (
 	"slices"
 
 	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/lookup"
 	"github.com/spf13/cobra"
 )
 
@@ -77,23 +78,29 @@ func Server(cmd *cobra.Command, args []string, toComplete string) ([]string, cob
 }
 
 // Namespace is a shell completion function that completes namespace names, just one completion.
+// It never blocks or prints errors, see lookup.CompleteNamespaces.
 func Namespace(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	if len(args) > 0 {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
 
-	kubeClientset := kube.ClientOrDie(cmd.Flag("kubeconfig").Value.String(), cmd.Flag("context").Value.String())
-
-	return kube.ListNamespaces(kubeClientset), cobra.ShellCompDirectiveNoFileComp
+	return lookup.CompleteNamespaces(cmd.Flag("kubeconfig").Value.String(), flagValue(cmd, "context")), cobra.ShellCompDirectiveNoFileComp
 }
 
 // ServiceAccount is a shell completion function that completes service account names, just one completion.
+// It never blocks or prints errors, see lookup.CompleteServiceAccounts.
 func ServiceAccount(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	if len(args) > 0 {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
 
-	kubeClientset := kube.ClientOrDie(cmd.Flag("kubeconfig").Value.String(), cmd.Flag("context").Value.String())
+	return lookup.CompleteServiceAccounts(cmd.Flag("kubeconfig").Value.String(), flagValue(cmd, "context"), flagValue(cmd, "namespace")), cobra.ShellCompDirectiveNoFileComp
+}
 
-	return kube.ListServiceAccounts(kubeClientset, cmd.Flag("namespace").Value.String()), cobra.ShellCompDirectiveNoFileComp
+// flagValue returns the value of the flag, empty if the command has no such flag.
+func flagValue(cmd *cobra.Command, name string) string {
+	if flag := cmd.Flag(name); flag != nil {
+		return flag.Value.String()
+	}
+	return ""
 }
@@ -0,0 +1,167 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package execcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/util"
+	clientauthv1 "k8s.io/client-go/pkg/apis/clientauthentication/v1"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// expirySkew is subtracted from the credential expiration, so that a cached
+// credential is never served right before it expires.
+const expirySkew = time.Minute
+
+var DefaultDir = filepath.Join(kube.DefaultStateDir, "exec-cache")
+
+// ErrNotWrapped is returned when no plugin is saved for a context.
+var ErrNotWrapped = errors.New("context exec plugin is not wrapped")
+
+// Plugin is the original exec plugin of a wrapped context. It is written once
+// when wrapping, so that it can always be restored.
+type Plugin struct {
+	Exec *clientcmdapi.ExecConfig `json:"exec"`
+	// Command is the exec plugin command resolved against the kubeconfig
+	// directory when it is a relative path.
+	Command string `json:"command"`
+}
+
+// Credential is the last credential returned by the plugin of a wrapped
+// context.
+type Credential struct {
+	Raw        json.RawMessage `json:"credential"`
+	Expiration *time.Time      `json:"expiration,omitempty"`
+}
+
+// ParseCredential parses the raw ExecCredential output of a plugin, the
+// expiration is nil if the credential has no expiration timestamp.
+func ParseCredential(raw []byte) (*Credential, error) {
+	var cred clientauthv1.ExecCredential
+	if err := json.Unmarshal(raw, &cred); err != nil {
+		return nil, err
+	}
+
+	c := &Credential{Raw: raw}
+	if cred.Status != nil && cred.Status.ExpirationTimestamp != nil {
+		expiration := cred.Status.ExpirationTimestamp.Time
+		c.Expiration = &expiration
+	}
+	return c, nil
+}
+
+// Valid checks if the cached credential can still be served at now.
+func (c *Credential) Valid(now time.Time) bool {
+	return len(c.Raw) > 0 && c.Expiration != nil && now.Add(expirySkew).Before(*c.Expiration)
+}
+
+// Cache is an on-disk store of wrapped exec plugins and their credentials
+// keyed by context name. Files are readable only by the current user, like
+// the kubeconfig holding the credentials otherwise.
+type Cache struct {
+	dir string
+}
+
+// New returns a cache stored in dir.
+func New(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// LoadPlugin loads the plugin of the context.
+func (c *Cache) LoadPlugin(name string) (*Plugin, error) {
+	var plugin Plugin
+	if err := c.load(c.file(name, "exec"), &plugin); err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotWrapped
+		}
+		return nil, err
+	}
+	return &plugin, nil
+}
+
+// SavePlugin saves the plugin of the context.
+func (c *Cache) SavePlugin(name string, plugin *Plugin) error {
+	return c.save(c.file(name, "exec"), plugin)
+}
+
+// LoadCredential loads the cached credential of the context, nil if not
+// cached.
+func (c *Cache) LoadCredential(name string) (*Credential, error) {
+	var cred Credential
+	if err := c.load(c.file(name, "credential"), &cred); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &cred, nil
+}
+
+// SaveCredential caches the credential of the context.
+func (c *Cache) SaveCredential(name string, cred *Credential) error {
+	return c.save(c.file(name, "credential"), cred)
+}
+
+// ClearCredential drops the cached credential of the context.
+func (c *Cache) ClearCredential(name string) error {
+	err := os.Remove(c.file(name, "credential"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Remove removes the plugin and the credential of the context.
+func (c *Cache) Remove(name string) error {
+	if err := c.ClearCredential(name); err != nil {
+		return err
+	}
+	err := os.Remove(c.file(name, "exec"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (c *Cache) load(file string, v any) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func (c *Cache) save(file string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return util.WriteFile(file, data)
+}
+
+func (c *Cache) file(name, kind string) string {
+	sum := sha256.Sum256([]byte(name))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:8])+"."+kind+".json")
+}
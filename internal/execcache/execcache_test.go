@@ -0,0 +1,110 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package execcache
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestParseCredential(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testdata := []struct {
+		credential string
+		valid      bool
+	}{
+		{
+			credential: `{"kind":"ExecCredential","apiVersion":"client.authentication.k8s.io/v1","status":{"token":"t","expirationTimestamp":"2025-01-01T01:00:00Z"}}`,
+			valid:      true,
+		},
+		{
+			credential: `{"kind":"ExecCredential","apiVersion":"client.authentication.k8s.io/v1","status":{"token":"t","expirationTimestamp":"2025-01-01T00:00:30Z"}}`,
+			valid:      false,
+		},
+		{
+			credential: `{"kind":"ExecCredential","apiVersion":"client.authentication.k8s.io/v1","status":{"token":"t"}}`,
+			valid:      false,
+		},
+	}
+
+	for _, test := range testdata {
+		cred, err := ParseCredential([]byte(test.credential))
+		if err != nil {
+			t.Fatalf("ParseCredential() failed: %s", err)
+		}
+		if cred.Valid(now) != test.valid {
+			t.Errorf("Valid() failed, credential: %s, expected: %t, got: %t", test.credential, test.valid, !test.valid)
+		}
+	}
+}
+
+func TestCache(t *testing.T) {
+	cache := New(t.TempDir())
+
+	if _, err := cache.LoadPlugin("context1"); !errors.Is(err, ErrNotWrapped) {
+		t.Errorf("LoadPlugin() expected error: %s, got: %v", ErrNotWrapped, err)
+	}
+
+	plugin := &Plugin{Exec: &clientcmdapi.ExecConfig{Command: "aws", Args: []string{"eks", "get-token"}}, Command: "aws"}
+	if err := cache.SavePlugin("context1", plugin); err != nil {
+		t.Fatalf("SavePlugin() failed: %s", err)
+	}
+	loaded, err := cache.LoadPlugin("context1")
+	if err != nil {
+		t.Fatalf("LoadPlugin() failed: %s", err)
+	}
+	if loaded.Exec.Command != "aws" || len(loaded.Exec.Args) != 2 {
+		t.Errorf("LoadPlugin() failed, expected exec: %v, got: %v", plugin.Exec, loaded.Exec)
+	}
+
+	if cred, err := cache.LoadCredential("context1"); err != nil || cred != nil {
+		t.Errorf("LoadCredential() failed, expected not cached, got: %v, %v", cred, err)
+	}
+	cred, _ := ParseCredential([]byte(`{"kind":"ExecCredential","apiVersion":"client.authentication.k8s.io/v1","status":{"token":"t","expirationTimestamp":"2025-01-01T01:00:00Z"}}`))
+	if err := cache.SaveCredential("context1", cred); err != nil {
+		t.Fatalf("SaveCredential() failed: %s", err)
+	}
+	if loaded, err := cache.LoadCredential("context1"); err != nil || !loaded.Expiration.Equal(*cred.Expiration) {
+		t.Errorf("LoadCredential() failed, expected expiration: %s, got: %v, %v", cred.Expiration, loaded, err)
+	}
+
+	// 凭据文件损坏时原插件仍然可以恢复
+	if err := os.WriteFile(cache.file("context1", "credential"), []byte("{corrupt"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.LoadCredential("context1"); err == nil {
+		t.Errorf("LoadCredential() expected error for corrupt credential")
+	}
+	if _, err := cache.LoadPlugin("context1"); err != nil {
+		t.Errorf("LoadPlugin() failed with corrupt credential: %s", err)
+	}
+
+	if err := cache.Remove("context1"); err != nil {
+		t.Fatalf("Remove() failed: %s", err)
+	}
+	if _, err := cache.LoadPlugin("context1"); !errors.Is(err, ErrNotWrapped) {
+		t.Errorf("LoadPlugin() after Remove() expected error: %s, got: %v", ErrNotWrapped, err)
+	}
+	if cred, err := cache.LoadCredential("context1"); err != nil || cred != nil {
+		t.Errorf("LoadCredential() after Remove() expected not cached, got: %v, %v", cred, err)
+	}
+}
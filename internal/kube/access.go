@@ -0,0 +1,132 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AccessCheck is an action checked with SelfSubjectAccessReview, either on a
+// resource or on a non-resource URL.
+type AccessCheck struct {
+	Verb        string
+	Resource    string
+	Group       string
+	Subresource string
+	Path        string
+}
+
+// ParseAccessCheck parses the resource in the form of
+// resource[.group][/subresource], or a non-resource URL starting with /.
+func ParseAccessCheck(verb, resource string) AccessCheck {
+	check := AccessCheck{Verb: verb}
+	if strings.HasPrefix(resource, "/") {
+		check.Path = resource
+		return check
+	}
+
+	resource, check.Subresource, _ = strings.Cut(resource, "/")
+	check.Resource, check.Group, _ = strings.Cut(resource, ".")
+	return check
+}
+
+func (c AccessCheck) String() string {
+	if len(c.Path) > 0 {
+		return c.Verb + " " + c.Path
+	}
+
+	s := c.Verb + " " + c.Resource
+	if len(c.Group) > 0 {
+		s += "." + c.Group
+	}
+	if len(c.Subresource) > 0 {
+		s += "/" + c.Subresource
+	}
+	return s
+}
+
+// AccessResult is the result of an access check.
+type AccessResult struct {
+	Allowed bool
+	Reason  string
+}
+
+// CanI checks the actions in the namespace with SelfSubjectAccessReviews,
+// all namespaces if namespace is empty.
+func CanI(ctx context.Context, kubeConfigFile, contextName, namespace string, checks []AccessCheck, timeout time.Duration) ([]AccessResult, error) {
+	clientset, _, err := timeoutClient(kubeConfigFile, contextName, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	results := make([]AccessResult, len(checks))
+	for i, check := range checks {
+		review := &authorizationv1.SelfSubjectAccessReview{}
+		if len(check.Path) > 0 {
+			review.Spec.NonResourceAttributes = &authorizationv1.NonResourceAttributes{
+				Verb: check.Verb,
+				Path: check.Path,
+			}
+		} else {
+			review.Spec.ResourceAttributes = &authorizationv1.ResourceAttributes{
+				Namespace:   namespace,
+				Verb:        check.Verb,
+				Group:       check.Group,
+				Resource:    check.Resource,
+				Subresource: check.Subresource,
+			}
+		}
+
+		resp, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+		if err != nil {
+			return nil, err
+		}
+		results[i] = AccessResult{
+			Allowed: resp.Status.Allowed,
+			Reason:  resp.Status.Reason,
+		}
+	}
+	return results, nil
+}
+
+// Rules lists the actions allowed in the namespace with
+// SelfSubjectRulesReview.
+func Rules(ctx context.Context, kubeConfigFile, contextName, namespace string, timeout time.Duration) (*authorizationv1.SubjectRulesReviewStatus, error) {
+	clientset, _, err := timeoutClient(kubeConfigFile, contextName, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	review, err := clientset.AuthorizationV1().SelfSubjectRulesReviews().Create(ctx, &authorizationv1.SelfSubjectRulesReview{
+		Spec: authorizationv1.SelfSubjectRulesReviewSpec{Namespace: namespace},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return &review.Status, nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestParseAccessCheck(t *testing.T) {
+	testdata := []struct {
+		verb     string
+		resource string
+		expected AccessCheck
+	}{
+		{"get", "pods", AccessCheck{Verb: "get", Resource: "pods"}},
+		{"create", "deployments.apps", AccessCheck{Verb: "create", Resource: "deployments", Group: "apps"}},
+		{"update", "deployments.apps/scale", AccessCheck{Verb: "update", Resource: "deployments", Group: "apps", Subresource: "scale"}},
+		{"get", "pods/log", AccessCheck{Verb: "get", Resource: "pods", Subresource: "log"}},
+		{"get", "/healthz", AccessCheck{Verb: "get", Path: "/healthz"}},
+	}
+
+	for _, test := range testdata {
+		got := ParseAccessCheck(test.verb, test.resource)
+		if got != test.expected {
+			t.Errorf("ParseAccessCheck() failed, expected: %+v, got: %+v", test.expected, got)
+		}
+		if got.String() != test.verb+" "+test.resource {
+			t.Errorf("AccessCheck.String() failed, expected: %s, got: %s", test.verb+" "+test.resource, got.String())
+		}
+	}
+}
+
+func TestCanI(t *testing.T) {
+	// 仅允许在 default 命名空间 get pods
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var review authorizationv1.SelfSubjectAccessReview
+		if _, _, err := scheme.Codecs.UniversalDeserializer().Decode(body, nil, &review); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		attrs := review.Spec.ResourceAttributes
+		review.Status.Allowed = attrs != nil && attrs.Namespace == "default" && attrs.Verb == "get" && attrs.Resource == "pods"
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(review)
+	}))
+	defer server.Close()
+
+	config := NewConfig()
+	config.Clusters["cluster"] = &clientcmdapi.Cluster{Server: server.URL, InsecureSkipTLSVerify: true}
+	config.AuthInfos["user"] = &clientcmdapi.AuthInfo{Token: "token"}
+	config.Contexts["context"] = &clientcmdapi.Context{Cluster: "cluster", AuthInfo: "user"}
+	file := filepath.Join(t.TempDir(), "config")
+	if err := clientcmd.WriteToFile(*config, file); err != nil {
+		t.Fatal(err)
+	}
+
+	checks := []AccessCheck{
+		ParseAccessCheck("get", "pods"),
+		ParseAccessCheck("delete", "pods"),
+		ParseAccessCheck("get", "/healthz"),
+	}
+	testdata := map[string][]bool{
+		"default": {true, false, false},
+		"prod":    {false, false, false},
+	}
+	for namespace, expected := range testdata {
+		results, err := CanI(context.Background(), file, "context", namespace, checks, time.Second)
+		if err != nil {
+			t.Fatalf("CanI() failed, error: %s", err)
+		}
+		for i, result := range results {
+			if result.Allowed != expected[i] {
+				t.Errorf("CanI() failed, namespace: %s, check: %s, expected: %t, got: %t", namespace, checks[i], expected[i], result.Allowed)
+			}
+		}
+	}
+}
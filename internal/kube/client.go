@@ -17,6 +17,8 @@ limitations under the License.
 package kube
 
 import (
+	"time"
+
 	"github.com/ketches/ktx/internal/output"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
@@ -54,6 +56,22 @@ func DiscoveryClient(kubeConfigFile, ctx string) (discovery.DiscoveryInterface,
 	return client.Discovery(), nil
 }
 
+// timeoutClient creates a new kubernetes client from the given kubeconfig
+// file and context, requests time out after the timeout.
+func timeoutClient(kubeConfigFile, ctx string, timeout time.Duration) (kubernetes.Interface, *rest.Config, error) {
+	restConfig, err := config(kubeConfigFile, ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	restConfig.Timeout = timeout
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	return clientset, restConfig, nil
+}
+
 // configOrDie creates a new kubernetes rest configOrDie from the
 // given kubeconfig file, and panics if it fails.
 func configOrDie(kubeConfigFile, ctx string) *rest.Config {
@@ -64,6 +82,16 @@ func configOrDie(kubeConfigFile, ctx string) *rest.Config {
 	return config
 }
 
+// ServerURL returns the server URL of the context cluster in the kubeconfig
+// file, the current context if ctx is empty.
+func ServerURL(kubeConfigFile, ctx string) (string, error) {
+	config, err := config(kubeConfigFile, ctx)
+	if err != nil {
+		return "", err
+	}
+	return config.Host, nil
+}
+
 // config creates a new kubernetes rest config from the given
 // kubeconfig file and context.
 func config(kubeConfigFile, ctx string) (*rest.Config, error) {
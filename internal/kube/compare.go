@@ -0,0 +1,261 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+var crdResource = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// APISurface is the API served by the cluster of a context.
+type APISurface struct {
+	Version string `json:"version"`
+	// Resources are the resource names by group version, subresources are
+	// not included.
+	Resources map[string][]string `json:"resources"`
+	// CRDs are the served versions by CRD name, nil if CRDs can not be listed.
+	CRDs map[string][]string `json:"crds,omitempty"`
+	// FailedGroupVersions are the group versions failing discovery, unknown
+	// whether served or not.
+	FailedGroupVersions []string `json:"failedGroupVersions,omitempty"`
+	Warnings            []string `json:"warnings,omitempty"`
+}
+
+// SetDiff is the difference between two sets.
+type SetDiff struct {
+	OnlyA []string `json:"onlyA,omitempty"`
+	OnlyB []string `json:"onlyB,omitempty"`
+}
+
+// Empty returns true if the sets are equal.
+func (d SetDiff) Empty() bool {
+	return len(d.OnlyA) == 0 && len(d.OnlyB) == 0
+}
+
+// CRDVersionDiff is a CRD served in different versions.
+type CRDVersionDiff struct {
+	Name string   `json:"name"`
+	A    []string `json:"a"`
+	B    []string `json:"b"`
+}
+
+// SurfaceDiff is the difference between the API surfaces A and B. Resources
+// are compared in the group versions served by both, and named as
+// group/version/resource, eg. apps/v1/deployments or v1/pods.
+type SurfaceDiff struct {
+	GroupVersions SetDiff          `json:"groupVersions"`
+	Resources     SetDiff          `json:"resources"`
+	CRDs          SetDiff          `json:"crds"`
+	CRDVersions   []CRDVersionDiff `json:"crdVersions,omitempty"`
+}
+
+// Empty returns true if the API surfaces are the same.
+func (d *SurfaceDiff) Empty() bool {
+	return d.GroupVersions.Empty() && d.Resources.Empty() && d.CRDs.Empty() && len(d.CRDVersions) == 0
+}
+
+// DiscoverAPISurface discovers the server version, the resources of all
+// group versions and the served versions of CRDs of the context cluster.
+func DiscoverAPISurface(ctx context.Context, kubeConfigFile, contextName string, timeout time.Duration) (*APISurface, error) {
+	clientset, restConfig, err := timeoutClient(kubeConfigFile, contextName, timeout)
+	if err != nil {
+		return nil, err
+	}
+	dyn, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	version, err := serverVersion(ctx, clientset.Discovery())
+	if err != nil {
+		return nil, err
+	}
+	surface, err := discoverAPISurface(ctx, clientset.Discovery(), dyn)
+	if err != nil {
+		return nil, err
+	}
+	surface.Version = version
+	return surface, nil
+}
+
+func discoverAPISurface(ctx context.Context, dc discovery.DiscoveryInterface, dyn dynamic.Interface) (*APISurface, error) {
+	surface := &APISurface{Resources: map[string][]string{}}
+
+	// 部分 API 组不可用（如 metrics-server 故障）时仍然比较其余的组
+	_, lists, err := dc.ServerGroupsAndResources()
+	if err != nil {
+		var failed *discovery.ErrGroupDiscoveryFailed
+		if !errors.As(err, &failed) {
+			return nil, err
+		}
+		for gv := range failed.Groups {
+			surface.FailedGroupVersions = append(surface.FailedGroupVersions, gv.String())
+		}
+		sort.Strings(surface.FailedGroupVersions)
+		surface.Warnings = append(surface.Warnings, err.Error())
+	}
+	for _, list := range lists {
+		if slices.Contains(surface.FailedGroupVersions, list.GroupVersion) {
+			continue
+		}
+		var names []string
+		for _, resource := range list.APIResources {
+			if !strings.Contains(resource.Name, "/") {
+				names = append(names, resource.Name)
+			}
+		}
+		sort.Strings(names)
+		surface.Resources[list.GroupVersion] = names
+	}
+
+	crds, err := listCRDVersions(ctx, dyn)
+	if err != nil {
+		surface.Warnings = append(surface.Warnings, fmt.Sprintf("unable to list CRDs: %s", err))
+	} else {
+		surface.CRDs = crds
+	}
+	return surface, nil
+}
+
+// listCRDVersions lists the served versions of the CRDs.
+func listCRDVersions(ctx context.Context, dyn dynamic.Interface) (map[string][]string, error) {
+	var (
+		crds = map[string][]string{}
+		opts = metav1.ListOptions{Limit: findPageSize}
+	)
+	for {
+		list, err := dyn.Resource(crdResource).List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range list.Items {
+			versions, _, _ := unstructured.NestedSlice(item.Object, "spec", "versions")
+			served := []string{}
+			for _, v := range versions {
+				version, ok := v.(map[string]any)
+				if !ok {
+					continue
+				}
+				if name, _ := version["name"].(string); len(name) > 0 && version["served"] == true {
+					served = append(served, name)
+				}
+			}
+			sort.Strings(served)
+			crds[item.GetName()] = served
+		}
+		if len(list.GetContinue()) == 0 {
+			return crds, nil
+		}
+		opts.Continue = list.GetContinue()
+	}
+}
+
+// CompareAPISurfaces compares the API surfaces. Group versions failing
+// discovery on either side are not compared, and CRDs are only compared if
+// both CRD lists are known.
+func CompareAPISurfaces(a, b *APISurface) *SurfaceDiff {
+	// 发现失败的组版本无法确定是否提供，不能作为差异
+	failed := slices.Concat(a.FailedGroupVersions, b.FailedGroupVersions)
+	known := func(resources map[string][]string) []string {
+		var gvs []string
+		for _, gv := range keys(resources) {
+			if !slices.Contains(failed, gv) {
+				gvs = append(gvs, gv)
+			}
+		}
+		return gvs
+	}
+	diff := &SurfaceDiff{
+		GroupVersions: setDiff(known(a.Resources), known(b.Resources)),
+	}
+
+	var resourcesA, resourcesB []string
+	for gv, names := range a.Resources {
+		if _, ok := b.Resources[gv]; !ok || slices.Contains(failed, gv) {
+			continue
+		}
+		for _, name := range names {
+			resourcesA = append(resourcesA, gv+"/"+name)
+		}
+		for _, name := range b.Resources[gv] {
+			resourcesB = append(resourcesB, gv+"/"+name)
+		}
+	}
+	diff.Resources = setDiff(resourcesA, resourcesB)
+
+	if a.CRDs == nil || b.CRDs == nil {
+		return diff
+	}
+	diff.CRDs = setDiff(keys(a.CRDs), keys(b.CRDs))
+	for _, name := range keys(a.CRDs) {
+		if versions, ok := b.CRDs[name]; ok && !slices.Equal(a.CRDs[name], versions) {
+			diff.CRDVersions = append(diff.CRDVersions, CRDVersionDiff{Name: name, A: a.CRDs[name], B: versions})
+		}
+	}
+	return diff
+}
+
+// setDiff returns the sorted items only in a and only in b.
+func setDiff(a, b []string) SetDiff {
+	inA, inB := map[string]bool{}, map[string]bool{}
+	for _, item := range a {
+		inA[item] = true
+	}
+	for _, item := range b {
+		inB[item] = true
+	}
+
+	var diff SetDiff
+	for _, item := range a {
+		if !inB[item] {
+			diff.OnlyA = append(diff.OnlyA, item)
+		}
+	}
+	for _, item := range b {
+		if !inA[item] {
+			diff.OnlyB = append(diff.OnlyB, item)
+		}
+	}
+	sort.Strings(diff.OnlyA)
+	sort.Strings(diff.OnlyB)
+	return diff
+}
+
+func keys(m map[string][]string) []string {
+	var items []string
+	for k := range m {
+		items = append(items, k)
+	}
+	sort.Strings(items)
+	return items
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestDiscoverAPISurface(t *testing.T) {
+	crd := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]any{"name": "widgets.example.com"},
+		"spec": map[string]any{"versions": []any{
+			map[string]any{"name": "v1", "served": true},
+			map[string]any{"name": "v1alpha1", "served": false},
+			map[string]any{"name": "v1beta1", "served": true},
+		}},
+	}}
+	listKinds := map[schema.GroupVersionResource]string{crdResource: "CustomResourceDefinitionList"}
+
+	dyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, crd)
+	surface, err := discoverAPISurface(context.Background(), fakeDiscovery().Discovery(), dyn)
+	if err != nil {
+		t.Fatalf("discoverAPISurface() failed, error: %s", err)
+	}
+	expected := map[string][]string{
+		"v1":      {"bindings", "nodes", "pods"},
+		"apps/v1": {"deployments"},
+	}
+	if !reflect.DeepEqual(surface.Resources, expected) {
+		t.Errorf("discoverAPISurface() failed, expected resources: %v, got: %v", expected, surface.Resources)
+	}
+	if got := surface.CRDs["widgets.example.com"]; !slices.Equal(got, []string{"v1", "v1beta1"}) {
+		t.Errorf("discoverAPISurface() failed, expected CRD versions: [v1 v1beta1], got: %v", got)
+	}
+
+	// 无权限列出 CRD 时只比较 API 资源
+	dyn = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, crd)
+	dyn.PrependReactor("list", "customresourcedefinitions", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(crdResource.GroupResource(), "", nil)
+	})
+	surface, err = discoverAPISurface(context.Background(), fakeDiscovery().Discovery(), dyn)
+	if err != nil {
+		t.Fatalf("discoverAPISurface() failed, error: %s", err)
+	}
+	if surface.CRDs != nil || len(surface.Warnings) != 1 {
+		t.Errorf("discoverAPISurface() failed, expected no CRDs and a warning, got: %v, %v", surface.CRDs, surface.Warnings)
+	}
+
+	// 部分组发现失败时记录失败的组版本
+	clientset := fakeDiscovery()
+	clientset.PrependReactor("get", "resource", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, &discovery.ErrGroupDiscoveryFailed{Groups: map[schema.GroupVersion]error{
+			{Group: "apps", Version: "v1"}: errors.New("the server is currently unable to handle the request"),
+		}}
+	})
+	surface, err = discoverAPISurface(context.Background(), clientset.Discovery(), dyn)
+	if err != nil {
+		t.Fatalf("discoverAPISurface() failed, error: %s", err)
+	}
+	if !slices.Equal(surface.FailedGroupVersions, []string{"apps/v1"}) {
+		t.Errorf("discoverAPISurface() failed, expected failed group versions: [apps/v1], got: %v", surface.FailedGroupVersions)
+	}
+	if _, ok := surface.Resources["apps/v1"]; ok {
+		t.Errorf("discoverAPISurface() failed, expected no resources of apps/v1, got: %v", surface.Resources)
+	}
+}
+
+func TestCompareAPISurfaces(t *testing.T) {
+	a := &APISurface{
+		Resources: map[string][]string{
+			"v1":            {"pods", "services"},
+			"batch/v1":      {"cronjobs", "jobs"},
+			"batch/v1beta1": {"cronjobs"},
+		},
+		CRDs: map[string][]string{
+			"widgets.example.com":          {"v1", "v1beta1"},
+			"certificates.cert-manager.io": {"v1"},
+		},
+	}
+	b := &APISurface{
+		Resources: map[string][]string{
+			"v1":                      {"pods", "services", "configmaps"},
+			"batch/v1":                {"jobs"},
+			"resource.k8s.io/v1beta1": {"resourceclaims"},
+		},
+		CRDs: map[string][]string{
+			"widgets.example.com": {"v1"},
+			"gadgets.example.com": {"v1"},
+		},
+	}
+
+	diff := CompareAPISurfaces(a, b)
+	expected := &SurfaceDiff{
+		GroupVersions: SetDiff{OnlyA: []string{"batch/v1beta1"}, OnlyB: []string{"resource.k8s.io/v1beta1"}},
+		Resources:     SetDiff{OnlyA: []string{"batch/v1/cronjobs"}, OnlyB: []string{"v1/configmaps"}},
+		CRDs:          SetDiff{OnlyA: []string{"certificates.cert-manager.io"}, OnlyB: []string{"gadgets.example.com"}},
+		CRDVersions:   []CRDVersionDiff{{Name: "widgets.example.com", A: []string{"v1", "v1beta1"}, B: []string{"v1"}}},
+	}
+	if !reflect.DeepEqual(diff, expected) {
+		t.Errorf("CompareAPISurfaces() failed, expected: %+v, got: %+v", expected, diff)
+	}
+	if diff.Empty() {
+		t.Errorf("CompareAPISurfaces() failed, expected differences")
+	}
+
+	// 任一方无法列出 CRD 时不比较 CRD
+	b.CRDs = nil
+	diff = CompareAPISurfaces(a, b)
+	if !diff.CRDs.Empty() || len(diff.CRDVersions) > 0 {
+		t.Errorf("CompareAPISurfaces() failed, expected CRDs not compared, got: %+v %+v", diff.CRDs, diff.CRDVersions)
+	}
+
+	if diff := CompareAPISurfaces(a, a); !diff.Empty() {
+		t.Errorf("CompareAPISurfaces() failed, expected no differences, got: %+v", diff)
+	}
+
+	// 一方发现失败的组版本不作为差异
+	failed := &APISurface{
+		Resources: map[string][]string{
+			"v1":       {"pods", "services"},
+			"batch/v1": {"cronjobs", "jobs"},
+		},
+		FailedGroupVersions: []string{"batch/v1beta1"},
+	}
+	if diff := CompareAPISurfaces(a, failed); !diff.Empty() {
+		t.Errorf("CompareAPISurfaces() failed, expected failed group versions not compared, got: %+v", diff)
+	}
+	if diff := CompareAPISurfaces(failed, a); !diff.Empty() {
+		t.Errorf("CompareAPISurfaces() failed, expected failed group versions not compared, got: %+v", diff)
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ketches/ktx/internal/output"
+	clientauthv1 "k8s.io/client-go/pkg/apis/clientauthentication/v1"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ExecAPIVersion is the exec credential API version written by ktx.
+const ExecAPIVersion = "client.authentication.k8s.io/v1"
+
+// KtxExecConfig returns an exec stanza that calls the running ktx binary
+// with the given arguments.
+func KtxExecConfig(args ...string) *clientcmdapi.ExecConfig {
+	command, err := os.Executable()
+	if err != nil {
+		output.Fatal("Failed to locate ktx executable: %s", err)
+	}
+
+	return &clientcmdapi.ExecConfig{
+		APIVersion:      ExecAPIVersion,
+		Command:         command,
+		Args:            args,
+		InstallHint:     "ktx is required to authenticate, see https://github.com/ketches/ktx",
+		InteractiveMode: clientcmdapi.IfAvailableExecInteractiveMode,
+	}
+}
+
+// IsKtxExec checks if the exec stanza calls the given ktx subcommand, and
+// returns the remaining arguments.
+func IsKtxExec(exec *clientcmdapi.ExecConfig, subcommand ...string) ([]string, bool) {
+	if exec == nil || !strings.HasPrefix(filepath.Base(exec.Command), "ktx") {
+		return nil, false
+	}
+	if len(exec.Args) < len(subcommand) {
+		return nil, false
+	}
+	for i, s := range subcommand {
+		if exec.Args[i] != s {
+			return nil, false
+		}
+	}
+	return exec.Args[len(subcommand):], true
+}
+
+// PrintExecCredential prints an ExecCredential with the given status to
+// stdout, as expected from an exec credential plugin.
+func PrintExecCredential(status *clientauthv1.ExecCredentialStatus) {
+	cred := clientauthv1.ExecCredential{
+		Status: status,
+	}
+	cred.APIVersion = ExecAPIVersion
+	cred.Kind = "ExecCredential"
+
+	v, err := json.Marshal(cred)
+	if err != nil {
+		output.Fatal("Failed to encode exec credential: %s", err)
+	}
+	fmt.Println(string(v))
+}
@@ -0,0 +1,305 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Severity is the severity of a kubeconfig issue.
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// Repair is the safe repair of a kubeconfig issue.
+type Repair int
+
+const (
+	RepairNone Repair = iota
+	RepairDropContext
+	RepairPruneCluster
+	RepairPruneUser
+	RepairUnsetCurrentContext
+	RepairChmod
+)
+
+func (r Repair) String() string {
+	switch r {
+	case RepairDropContext:
+		return "drop context"
+	case RepairPruneCluster:
+		return "prune cluster"
+	case RepairPruneUser:
+		return "prune user"
+	case RepairUnsetCurrentContext:
+		return "unset current-context"
+	case RepairChmod:
+		return "chmod 0600"
+	default:
+		return ""
+	}
+}
+
+// Issue is a problem found in the kubeconfig.
+type Issue struct {
+	Severity Severity
+	// Kind is one of file, context, cluster or user.
+	Kind    string
+	Name    string
+	Message string
+	Repair  Repair
+}
+
+// Diagnose checks the kubeconfig loaded from file for broken references,
+// unusable credentials and unsafe permissions. Issues are sorted by severity.
+func Diagnose(config *clientcmdapi.Config, file string) []*Issue {
+	var issues []*Issue
+	add := func(severity Severity, kind, name string, repair Repair, format string, a ...any) {
+		issues = append(issues, &Issue{
+			Severity: severity,
+			Kind:     kind,
+			Name:     name,
+			Message:  fmt.Sprintf(format, a...),
+			Repair:   repair,
+		})
+	}
+
+	if info, err := os.Stat(file); err == nil && runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		add(SeverityWarning, "file", file, RepairChmod, "permission %s allows access by group or others", info.Mode().Perm())
+	}
+
+	if len(config.CurrentContext) > 0 {
+		if _, ok := config.Contexts[config.CurrentContext]; !ok {
+			add(SeverityWarning, "context", config.CurrentContext, RepairUnsetCurrentContext, "current-context not found")
+		}
+	}
+
+	usedClusters, usedUsers := map[string]bool{}, map[string]bool{}
+	for name, ctx := range config.Contexts {
+		usedClusters[ctx.Cluster] = true
+		usedUsers[ctx.AuthInfo] = true
+
+		if _, ok := config.Clusters[ctx.Cluster]; !ok {
+			add(SeverityError, "context", name, RepairDropContext, "cluster %q not found", ctx.Cluster)
+		}
+		if _, ok := config.AuthInfos[ctx.AuthInfo]; !ok {
+			add(SeverityError, "context", name, RepairDropContext, "user %q not found", ctx.AuthInfo)
+		}
+	}
+
+	servers := map[string][]string{}
+	for name, cluster := range config.Clusters {
+		if !usedClusters[name] {
+			add(SeverityWarning, "cluster", name, RepairPruneCluster, "not used by any context")
+		}
+		if len(cluster.Server) == 0 {
+			add(SeverityError, "cluster", name, RepairNone, "server is empty")
+		} else {
+			servers[cluster.Server] = append(servers[cluster.Server], name)
+		}
+
+		if data, err := loadData(cluster.CertificateAuthorityData, cluster.CertificateAuthority, origin(cluster.LocationOfOrigin, file)); err != nil {
+			add(SeverityError, "cluster", name, RepairNone, "certificate-authority: %s", err)
+		} else if data != nil {
+			if _, err := ParseCertificates(data); err != nil {
+				add(SeverityError, "cluster", name, RepairNone, "certificate-authority: %s", err)
+			}
+		}
+	}
+	for server, names := range servers {
+		if len(names) > 1 {
+			sort.Strings(names)
+			for _, name := range names {
+				add(SeverityInfo, "cluster", name, RepairNone, "server %s is shared with %s", server, strings.Join(without(names, name), ", "))
+			}
+		}
+	}
+
+	for name, user := range config.AuthInfos {
+		if !usedUsers[name] {
+			add(SeverityWarning, "user", name, RepairPruneUser, "not used by any context")
+		}
+		dir := origin(user.LocationOfOrigin, file)
+
+		if len(user.TokenFile) > 0 {
+			if _, err := loadData(nil, user.TokenFile, dir); err != nil {
+				add(SeverityError, "user", name, RepairNone, "token-file: %s", err)
+			}
+		}
+
+		cert, err := loadData(user.ClientCertificateData, user.ClientCertificate, dir)
+		if err != nil {
+			add(SeverityError, "user", name, RepairNone, "client-certificate: %s", err)
+		} else if cert != nil {
+			if certs, err := ParseCertificates(cert); err != nil {
+				add(SeverityError, "user", name, RepairNone, "client-certificate: %s", err)
+				cert = nil
+			} else if time.Now().After(certs[0].NotAfter) {
+				add(SeverityError, "user", name, RepairNone, "client-certificate expired at %s", certs[0].NotAfter.Local().Format(time.DateTime))
+			}
+		}
+
+		key, err := loadData(user.ClientKeyData, user.ClientKey, dir)
+		if err != nil {
+			add(SeverityError, "user", name, RepairNone, "client-key: %s", err)
+		} else if key != nil {
+			if block, _ := pem.Decode(key); block == nil {
+				add(SeverityError, "user", name, RepairNone, "client-key: no PEM data found")
+				key = nil
+			}
+		}
+		if cert != nil && key != nil {
+			if _, err := tls.X509KeyPair(cert, key); err != nil {
+				add(SeverityError, "user", name, RepairNone, "client-certificate and client-key do not match: %s", err)
+			}
+		}
+
+		if user.Exec != nil {
+			command := user.Exec.Command
+			// 与 client-go 一致，包含路径分隔符的相对路径基于 kubeconfig 所在目录
+			if strings.ContainsRune(command, filepath.Separator) && !filepath.IsAbs(command) {
+				command = filepath.Join(dir, command)
+			}
+			if _, err := exec.LookPath(command); err != nil {
+				add(SeverityError, "user", name, RepairNone, "exec plugin %s not found in PATH", user.Exec.Command)
+			}
+		}
+		if user.AuthProvider != nil {
+			add(SeverityWarning, "user", name, RepairNone, "auth-provider %s is removed from kubectl, run `ktx migrate`", user.AuthProvider.Name)
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Severity != issues[j].Severity {
+			return issues[i].Severity > issues[j].Severity
+		}
+		if issues[i].Kind != issues[j].Kind {
+			return issues[i].Kind < issues[j].Kind
+		}
+		if issues[i].Name != issues[j].Name {
+			return issues[i].Name < issues[j].Name
+		}
+		return issues[i].Message < issues[j].Message
+	})
+	return issues
+}
+
+// ApplyRepair applies the repair of the issue to the kubeconfig, the file
+// permission is changed directly while config changes need to be saved.
+func ApplyRepair(config *clientcmdapi.Config, file string, issue *Issue) error {
+	switch issue.Repair {
+	case RepairDropContext:
+		delete(config.Contexts, issue.Name)
+		if config.CurrentContext == issue.Name {
+			config.CurrentContext = ""
+		}
+	case RepairPruneCluster:
+		delete(config.Clusters, issue.Name)
+	case RepairPruneUser:
+		delete(config.AuthInfos, issue.Name)
+	case RepairUnsetCurrentContext:
+		config.CurrentContext = ""
+	case RepairChmod:
+		return os.Chmod(file, 0600)
+	default:
+		return errors.New("no safe repair")
+	}
+	return nil
+}
+
+// ParseCertificates parses the PEM encoded certificates.
+func ParseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM certificate found")
+	}
+	return certs, nil
+}
+
+// loadData returns the inline data, or reads the file relative to dir. Both
+// are nil if neither is set.
+func loadData(data []byte, file, dir string) ([]byte, error) {
+	if len(data) > 0 || len(file) == 0 {
+		return data, nil
+	}
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(dir, file)
+	}
+	return os.ReadFile(file)
+}
+
+// origin returns the directory relative paths of an entry are resolved
+// against.
+func origin(location, file string) string {
+	if len(location) > 0 {
+		return filepath.Dir(location)
+	}
+	return filepath.Dir(file)
+}
+
+func without(names []string, name string) []string {
+	var out []string
+	for _, n := range names {
+		if n != name {
+			out = append(out, n)
+		}
+	}
+	return out
+}
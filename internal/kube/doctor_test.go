@@ -0,0 +1,145 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func testCertificate(t *testing.T, notAfter time.Time) (cert, key []byte) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    notAfter.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestDiagnose(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	expiredCert, expiredKey := testCertificate(t, time.Now().Add(-time.Hour))
+	validCert, validKey := testCertificate(t, time.Now().Add(time.Hour))
+
+	config := NewConfig()
+	config.CurrentContext = "gone"
+	config.Clusters["a"] = &clientcmdapi.Cluster{Server: "https://1.2.3.4"}
+	config.Clusters["b"] = &clientcmdapi.Cluster{Server: "https://1.2.3.4", CertificateAuthorityData: []byte("invalid")}
+	config.Clusters["orphan"] = &clientcmdapi.Cluster{Server: "https://5.6.7.8"}
+	config.AuthInfos["valid"] = &clientcmdapi.AuthInfo{ClientCertificateData: validCert, ClientKeyData: validKey}
+	config.AuthInfos["expired"] = &clientcmdapi.AuthInfo{ClientCertificateData: expiredCert, ClientKeyData: expiredKey}
+	config.AuthInfos["mismatch"] = &clientcmdapi.AuthInfo{ClientCertificateData: validCert, ClientKeyData: expiredKey}
+	config.AuthInfos["exec"] = &clientcmdapi.AuthInfo{Exec: &clientcmdapi.ExecConfig{Command: "ktx-no-such-plugin"}}
+	config.AuthInfos["token"] = &clientcmdapi.AuthInfo{TokenFile: "missing-token"}
+	config.AuthInfos["orphan"] = &clientcmdapi.AuthInfo{}
+	config.Contexts["valid"] = &clientcmdapi.Context{Cluster: "a", AuthInfo: "valid"}
+	config.Contexts["expired"] = &clientcmdapi.Context{Cluster: "a", AuthInfo: "expired"}
+	config.Contexts["mismatch"] = &clientcmdapi.Context{Cluster: "b", AuthInfo: "mismatch"}
+	config.Contexts["exec"] = &clientcmdapi.Context{Cluster: "a", AuthInfo: "exec"}
+	config.Contexts["token"] = &clientcmdapi.Context{Cluster: "a", AuthInfo: "token"}
+	config.Contexts["dangling"] = &clientcmdapi.Context{Cluster: "none", AuthInfo: "valid"}
+
+	testdata := []struct {
+		severity Severity
+		kind     string
+		name     string
+		message  string
+		repair   Repair
+	}{
+		{SeverityWarning, "file", file, "permission", RepairChmod},
+		{SeverityWarning, "context", "gone", "current-context not found", RepairUnsetCurrentContext},
+		{SeverityError, "context", "dangling", `cluster "none" not found`, RepairDropContext},
+		{SeverityWarning, "cluster", "orphan", "not used", RepairPruneCluster},
+		{SeverityWarning, "user", "orphan", "not used", RepairPruneUser},
+		{SeverityError, "cluster", "b", "certificate-authority", RepairNone},
+		{SeverityInfo, "cluster", "a", "is shared with b", RepairNone},
+		{SeverityError, "user", "expired", "client-certificate expired", RepairNone},
+		{SeverityError, "user", "mismatch", "do not match", RepairNone},
+		{SeverityError, "user", "exec", "exec plugin ktx-no-such-plugin not found", RepairNone},
+		{SeverityError, "user", "token", "token-file", RepairNone},
+	}
+
+	issues := Diagnose(config, file)
+	for _, test := range testdata {
+		var found bool
+		for _, issue := range issues {
+			if issue.Kind == test.kind && issue.Name == test.name && strings.Contains(issue.Message, test.message) {
+				found = true
+				if issue.Severity != test.severity || issue.Repair != test.repair {
+					t.Errorf("Diagnose() failed, %s %s: expected %s/%s, got %s/%s", test.kind, test.name, test.severity, test.repair, issue.Severity, issue.Repair)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("Diagnose() failed, expected issue of %s %s: %s", test.kind, test.name, test.message)
+		}
+	}
+	for _, issue := range issues {
+		if issue.Name == "valid" {
+			t.Errorf("Diagnose() failed, unexpected issue of %s %s: %s", issue.Kind, issue.Name, issue.Message)
+		}
+	}
+
+	for _, issue := range issues {
+		if issue.Repair != RepairNone {
+			if err := ApplyRepair(config, file, issue); err != nil {
+				t.Errorf("ApplyRepair() failed, %s %s: %s", issue.Kind, issue.Name, err)
+			}
+		}
+	}
+	if _, ok := config.Contexts["dangling"]; ok {
+		t.Errorf("ApplyRepair() failed, expected context dangling dropped")
+	}
+	if _, ok := config.Clusters["orphan"]; ok {
+		t.Errorf("ApplyRepair() failed, expected cluster orphan pruned")
+	}
+	if len(config.CurrentContext) > 0 {
+		t.Errorf("ApplyRepair() failed, expected current-context unset, got %s", config.CurrentContext)
+	}
+	if info, err := os.Stat(file); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("ApplyRepair() failed, expected file mode 0600")
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ketches/ktx/internal/util"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	// ExpiryWarning is the remaining time below which an expiry is warned.
+	ExpiryWarning = 30 * 24 * time.Hour
+	// ExpiryCritical is the remaining time below which an expiry is critical.
+	ExpiryCritical = 7 * 24 * time.Hour
+)
+
+// Expiry is the expiry of a credential of a context.
+type Expiry struct {
+	Context string
+	// Credential is one of client-certificate, certificate-authority or token.
+	Credential string
+	Subject    string
+	NotAfter   time.Time
+}
+
+// Remaining returns the time remaining until the credential expires.
+func (e *Expiry) Remaining(now time.Time) time.Duration {
+	return e.NotAfter.Sub(now)
+}
+
+// ContextExpiries returns the expiries of the client certificate, the CA
+// bundle and the JWT bearer token of the context, sorted by expiry time.
+// Credentials which can not be read or parsed are skipped, they are reported
+// by Diagnose.
+func ContextExpiries(config *clientcmdapi.Config, file, contextName string) []*Expiry {
+	ctx, ok := config.Contexts[contextName]
+	if !ok {
+		return nil
+	}
+
+	var expiries []*Expiry
+	if cluster, ok := config.Clusters[ctx.Cluster]; ok {
+		data, _ := loadData(cluster.CertificateAuthorityData, cluster.CertificateAuthority, origin(cluster.LocationOfOrigin, file))
+		if certs, err := ParseCertificates(data); err == nil {
+			// CA 包中可能有多个证书，取最早过期的一个
+			earliest := certs[0]
+			for _, cert := range certs[1:] {
+				if cert.NotAfter.Before(earliest.NotAfter) {
+					earliest = cert
+				}
+			}
+			expiries = append(expiries, &Expiry{
+				Context:    contextName,
+				Credential: "certificate-authority",
+				Subject:    earliest.Subject.CommonName,
+				NotAfter:   earliest.NotAfter,
+			})
+		}
+	}
+
+	if user, ok := config.AuthInfos[ctx.AuthInfo]; ok {
+		dir := origin(user.LocationOfOrigin, file)
+
+		data, _ := loadData(user.ClientCertificateData, user.ClientCertificate, dir)
+		if certs, err := ParseCertificates(data); err == nil {
+			expiries = append(expiries, &Expiry{
+				Context:    contextName,
+				Credential: "client-certificate",
+				Subject:    certs[0].Subject.CommonName,
+				NotAfter:   certs[0].NotAfter,
+			})
+		}
+
+		token := user.Token
+		if len(token) == 0 && len(user.TokenFile) > 0 {
+			data, _ := loadData(nil, user.TokenFile, dir)
+			token = strings.TrimSpace(string(data))
+		}
+		if expiry, ok := util.JWTExpiry(token); ok {
+			var subject string
+			if claims, err := util.JWTClaims(token); err == nil {
+				subject, _ = claims["sub"].(string)
+			}
+			expiries = append(expiries, &Expiry{
+				Context:    contextName,
+				Credential: "token",
+				Subject:    subject,
+				NotAfter:   expiry,
+			})
+		}
+	}
+
+	sort.Slice(expiries, func(i, j int) bool {
+		return expiries[i].NotAfter.Before(expiries[j].NotAfter)
+	})
+	return expiries
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestContextExpiries(t *testing.T) {
+	var (
+		now       = time.Now().Truncate(time.Second)
+		caCert, _ = testCertificate(t, now.Add(365*24*time.Hour))
+		cert, _   = testCertificate(t, now.Add(3*24*time.Hour))
+		payload   = base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, `{"sub":"bob","exp":%d}`, now.Add(time.Hour).Unix()))
+	)
+
+	config := NewConfig()
+	config.Clusters["cluster"] = &clientcmdapi.Cluster{Server: "https://1.2.3.4", CertificateAuthorityData: caCert}
+	config.AuthInfos["cert"] = &clientcmdapi.AuthInfo{ClientCertificateData: cert}
+	config.AuthInfos["token"] = &clientcmdapi.AuthInfo{Token: "header." + payload + ".signature"}
+	config.AuthInfos["opaque"] = &clientcmdapi.AuthInfo{Token: "opaque"}
+	config.Contexts["cert"] = &clientcmdapi.Context{Cluster: "cluster", AuthInfo: "cert"}
+	config.Contexts["token"] = &clientcmdapi.Context{Cluster: "cluster", AuthInfo: "token"}
+	config.Contexts["opaque"] = &clientcmdapi.Context{Cluster: "missing", AuthInfo: "opaque"}
+
+	testdata := map[string][]string{
+		"cert":   {"client-certificate", "certificate-authority"},
+		"token":  {"token", "certificate-authority"},
+		"opaque": nil,
+	}
+	for name, expected := range testdata {
+		expiries := ContextExpiries(config, "", name)
+		var got []string
+		for _, expiry := range expiries {
+			got = append(got, expiry.Credential)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(expected) {
+			t.Errorf("ContextExpiries() failed, context: %s, expected: %v, got: %v", name, expected, got)
+		}
+	}
+
+	if expiries := ContextExpiries(config, "", "token"); len(expiries) == 0 || expiries[0].Subject != "bob" || !expiries[0].NotAfter.Equal(now.Add(time.Hour)) {
+		t.Errorf("ContextExpiries() failed, expected token of bob expiring at %s", now.Add(time.Hour))
+	}
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// ErrUnknownResource is returned by ResolveResource if the server does not
+// serve the kind.
+var ErrUnknownResource = errors.New("the server doesn't have the resource type")
+
+// findPageSize is the number of objects listed per request by Find.
+const findPageSize = 500
+
+// Match is a resource found by Find.
+type Match struct {
+	Context   string
+	Namespace string
+	Kind      string
+	Name      string
+	Created   time.Time
+}
+
+// Find finds the resources of the kind whose names match the pattern in all
+// namespaces of the context, or in the namespace of the context if listing
+// all namespaces is forbidden. The kind is a resource name, singular name,
+// short name or kind, optionally qualified by the group, eg. deploy or
+// deployments.apps.
+func Find(ctx context.Context, kubeConfigFile, contextName, kind string, pattern *regexp.Regexp, timeout time.Duration) ([]*Match, error) {
+	clientset, restConfig, err := timeoutClient(kubeConfigFile, contextName, timeout)
+	if err != nil {
+		return nil, err
+	}
+	dyn, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeConfigFile
+	namespace, _, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules,
+		&clientcmd.ConfigOverrides{CurrentContext: contextName}).Namespace()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	matches, err := find(ctx, clientset.Discovery(), dyn, namespace, kind, pattern)
+	for _, match := range matches {
+		match.Context = contextName
+	}
+	return matches, err
+}
+
+func find(ctx context.Context, dc discovery.DiscoveryInterface, dyn dynamic.Interface, namespace, kind string, pattern *regexp.Regexp) ([]*Match, error) {
+	type resolved struct {
+		gvr      schema.GroupVersionResource
+		resource metav1.APIResource
+		err      error
+	}
+	// 发现接口不支持 context，避免取消后仍然等待
+	done := make(chan resolved, 1)
+	go func() {
+		gvr, resource, err := ResolveResource(dc, kind)
+		done <- resolved{gvr, resource, err}
+	}()
+	var r resolved
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r = <-done:
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	var ri dynamic.ResourceInterface = dyn.Resource(r.gvr)
+	if r.resource.Namespaced {
+		ri = dyn.Resource(r.gvr).Namespace(metav1.NamespaceAll)
+	}
+	matches, err := listMatches(ctx, ri, r.resource.Kind, pattern)
+	// 无权限列出所有命名空间时，在 context 的命名空间中查找
+	if apierrors.IsForbidden(err) && r.resource.Namespaced && len(namespace) > 0 {
+		matches, err = listMatches(ctx, dyn.Resource(r.gvr).Namespace(namespace), r.resource.Kind, pattern)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Namespace != matches[j].Namespace {
+			return matches[i].Namespace < matches[j].Namespace
+		}
+		return matches[i].Name < matches[j].Name
+	})
+	return matches, nil
+}
+
+func listMatches(ctx context.Context, ri dynamic.ResourceInterface, kind string, pattern *regexp.Regexp) ([]*Match, error) {
+	var matches []*Match
+	opts := metav1.ListOptions{Limit: findPageSize}
+	for {
+		objects, err := ri.List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range objects.Items {
+			if pattern.MatchString(obj.GetName()) {
+				matches = append(matches, &Match{
+					Namespace: obj.GetNamespace(),
+					Kind:      kind,
+					Name:      obj.GetName(),
+					Created:   obj.GetCreationTimestamp().Time,
+				})
+			}
+		}
+		if opts.Continue = objects.GetContinue(); len(opts.Continue) == 0 {
+			return matches, nil
+		}
+	}
+}
+
+// ResolveResource resolves the kind to the listable resource of the
+// preferred version of its group. See Find for the forms of the kind.
+func ResolveResource(dc discovery.DiscoveryInterface, kind string) (schema.GroupVersionResource, metav1.APIResource, error) {
+	groups, lists, err := dc.ServerGroupsAndResources()
+	// 部分 API 组发现失败时使用已发现的资源
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return schema.GroupVersionResource{}, metav1.APIResource{}, err
+	}
+
+	name, group, qualified := strings.Cut(strings.ToLower(kind), ".")
+	preferred := make(map[string]string, len(groups))
+	for _, g := range groups {
+		preferred[g.Name] = g.PreferredVersion.GroupVersion
+	}
+
+	// 按服务端返回的顺序匹配，核心组优先
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil || preferred[gv.Group] != list.GroupVersion || (qualified && gv.Group != group) {
+			continue
+		}
+		for _, resource := range list.APIResources {
+			if strings.Contains(resource.Name, "/") || !slices.Contains(resource.Verbs, "list") {
+				continue
+			}
+			if resource.Name == name || resource.SingularName == name ||
+				strings.ToLower(resource.Kind) == name || slices.Contains(resource.ShortNames, name) {
+				return gv.WithResource(resource.Name), resource, nil
+			}
+		}
+	}
+	return schema.GroupVersionResource{}, metav1.APIResource{}, fmt.Errorf("%w %q", ErrUnknownResource, kind)
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func fakeDiscovery() *fake.Clientset {
+	clientset := fake.NewClientset()
+	clientset.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "pods", SingularName: "pod", Kind: "Pod", Namespaced: true, ShortNames: []string{"po"}, Verbs: []string{"get", "list"}},
+				{Name: "pods/log", Kind: "Pod", Namespaced: true, Verbs: []string{"get"}},
+				{Name: "nodes", SingularName: "node", Kind: "Node", ShortNames: []string{"no"}, Verbs: []string{"get", "list"}},
+				{Name: "bindings", SingularName: "binding", Kind: "Binding", Namespaced: true, Verbs: []string{"create"}},
+			},
+		},
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "deployments", SingularName: "deployment", Kind: "Deployment", Namespaced: true, ShortNames: []string{"deploy"}, Verbs: []string{"get", "list"}},
+			},
+		},
+	}
+	return clientset
+}
+
+func TestResolveResource(t *testing.T) {
+	dc := fakeDiscovery().Discovery()
+
+	testdata := []struct {
+		kind     string
+		expected string
+	}{
+		{"pods", "/v1, Resource=pods"},
+		{"pod", "/v1, Resource=pods"},
+		{"po", "/v1, Resource=pods"},
+		{"Pod", "/v1, Resource=pods"},
+		{"deploy", "apps/v1, Resource=deployments"},
+		{"deployments.apps", "apps/v1, Resource=deployments"},
+		{"nodes", "/v1, Resource=nodes"},
+		{"deployments.batch", ""},
+		{"bindings", ""},
+		{"pods/log", ""},
+	}
+	for _, test := range testdata {
+		gvr, _, err := ResolveResource(dc, test.kind)
+		got := gvr.String()
+		if err != nil {
+			got = ""
+		}
+		if got != test.expected {
+			t.Errorf("ResolveResource() failed, kind: %s, expected: %q, got: %q, error: %v", test.kind, test.expected, got, err)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	object := func(apiVersion, kind, namespace, name string) runtime.Object {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind(kind)
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		return obj
+	}
+	listKinds := map[schema.GroupVersionResource]string{
+		{Version: "v1", Resource: "pods"}:                       "PodList",
+		{Version: "v1", Resource: "nodes"}:                      "NodeList",
+		{Group: "apps", Version: "v1", Resource: "deployments"}: "DeploymentList",
+	}
+	objects := []runtime.Object{
+		object("v1", "Pod", "payments", "checkout-7d9f-abc"),
+		object("v1", "Pod", "payments", "cart-5c6d-xyz"),
+		object("v1", "Pod", "shop", "checkout-1a2b-def"),
+		object("v1", "Node", "", "node-1"),
+		object("apps/v1", "Deployment", "shop", "checkout"),
+	}
+	dc := fakeDiscovery().Discovery()
+
+	testdata := []struct {
+		kind      string
+		pattern   string
+		forbidden bool
+		expected  []string
+	}{
+		{"po", "checkout", false, []string{"payments/Pod/checkout-7d9f-abc", "shop/Pod/checkout-1a2b-def"}},
+		{"deploy", "^checkout$", false, []string{"shop/Deployment/checkout"}},
+		{"no", "node", false, []string{"/Node/node-1"}},
+		{"pods", "checkout", true, []string{"shop/Pod/checkout-1a2b-def"}},
+	}
+	for _, test := range testdata {
+		dyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...)
+		if test.forbidden {
+			dyn.PrependReactor("list", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				if action.GetNamespace() == metav1.NamespaceAll {
+					return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", nil)
+				}
+				return false, nil, nil
+			})
+		}
+
+		matches, err := find(context.Background(), dc, dyn, "shop", test.kind, regexp.MustCompile(test.pattern))
+		if err != nil {
+			t.Errorf("find() failed, kind: %s, error: %s", test.kind, err)
+			continue
+		}
+		var got []string
+		for _, match := range matches {
+			got = append(got, match.Namespace+"/"+match.Kind+"/"+match.Name)
+		}
+		if !slices.Equal(got, test.expected) {
+			t.Errorf("find() failed, kind: %s, pattern: %s, expected: %v, got: %v", test.kind, test.pattern, test.expected, got)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/kubernetes"
+)
+
+// inventoryPageSize is the number of nodes listed per request by
+// CollectInventory.
+const inventoryPageSize = 500
+
+// Inventory is the inventory of the cluster of a context. Nodes and
+// Namespaces are nil if they can not be listed, the errors are kept in
+// Warnings by what was listed.
+type Inventory struct {
+	Version       string            `json:"version"`
+	Platform      string            `json:"platform,omitempty"`
+	Distributions []string          `json:"distributions,omitempty"`
+	Providers     []string          `json:"providers,omitempty"`
+	Regions       []string          `json:"regions,omitempty"`
+	Nodes         *int              `json:"nodes,omitempty"`
+	Roles         map[string]int    `json:"roles,omitempty"`
+	Namespaces    *int              `json:"namespaces,omitempty"`
+	CNI           []string          `json:"cni,omitempty"`
+	Ingress       []string          `json:"ingress,omitempty"`
+	Warnings      map[string]string `json:"warnings,omitempty"`
+}
+
+// 从 API 组推断的 CNI 插件
+var cniGroups = map[string]string{
+	"cilium.io":             "Cilium",
+	"crd.projectcalico.org": "Calico",
+	"projectcalico.org":     "Calico",
+	"operator.tigera.io":    "Calico",
+	"crd.antrea.io":         "Antrea",
+	"kubeovn.io":            "Kube-OVN",
+	"k8s.ovn.org":           "OVN-Kubernetes",
+	"k8s.cni.cncf.io":       "Multus",
+	"crd.k8s.amazonaws.com": "AWS VPC CNI",
+	"network.openshift.io":  "OpenShift SDN",
+}
+
+// 从 API 组推断的 Ingress 控制器、网关与服务网格
+var ingressGroups = map[string]string{
+	"networking.istio.io":       "Istio",
+	"traefik.io":                "Traefik",
+	"traefik.containo.us":       "Traefik",
+	"configuration.konghq.com":  "Kong",
+	"projectcontour.io":         "Contour",
+	"k8s.nginx.org":             "NGINX Ingress",
+	"getambassador.io":          "Emissary",
+	"gateway.envoyproxy.io":     "Envoy Gateway",
+	"elbv2.k8s.aws":             "AWS Load Balancer Controller",
+	"route.openshift.io":        "OpenShift Router",
+	"gateway.networking.k8s.io": "Gateway API",
+}
+
+// 节点 providerID 的 scheme 对应的云厂商，未知的 scheme 原样输出
+var providerSchemes = map[string]string{
+	"aws":          "AWS",
+	"gce":          "GCP",
+	"azure":        "Azure",
+	"digitalocean": "DigitalOcean",
+	"linode":       "Linode",
+	"hcloud":       "Hetzner",
+	"ibm":          "IBM Cloud",
+	"oci":          "Oracle Cloud",
+	"openstack":    "OpenStack",
+	"vsphere":      "vSphere",
+	"equinixmetal": "Equinix Metal",
+}
+
+// 服务端版本号中的发行版标记
+var versionDistributions = map[string]string{
+	"-eks-":    "EKS",
+	"-gke.":    "GKE",
+	"-aliyun.": "ACK",
+	"+k3s":     "k3s",
+	"+rke2":    "RKE2",
+	"+k0s":     "k0s",
+	"+vmware":  "Tanzu",
+}
+
+// 节点标签中的发行版标记
+var labelDistributions = map[string]string{
+	"eks.amazonaws.com/nodegroup":     "EKS",
+	"cloud.google.com/gke-nodepool":   "GKE",
+	"kubernetes.azure.com/cluster":    "AKS",
+	"doks.digitalocean.com/node-pool": "DOKS",
+	"node.openshift.io/os_id":         "OpenShift",
+	"k3s.io/hostname":                 "k3s",
+	"minikube.k8s.io/name":            "minikube",
+	"microk8s.io/cluster":             "MicroK8s",
+}
+
+// CollectInventory collects the inventory of the context cluster: the server
+// version, nodes, namespaces, and the distribution, cloud provider, CNI and
+// ingress hints inferred from them and the served API groups.
+func CollectInventory(ctx context.Context, kubeConfigFile, contextName string, timeout time.Duration) (*Inventory, error) {
+	clientset, _, err := timeoutClient(kubeConfigFile, contextName, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	info, err := serverInfo(ctx, clientset.Discovery())
+	if err != nil {
+		return nil, err
+	}
+	return collectInventory(ctx, clientset, info), nil
+}
+
+func collectInventory(ctx context.Context, clientset kubernetes.Interface, info *version.Info) *Inventory {
+	inventory := &Inventory{
+		Version:  info.GitVersion,
+		Platform: info.Platform,
+		Warnings: map[string]string{},
+	}
+
+	var groups []string
+	if list, err := clientset.Discovery().ServerGroups(); err != nil {
+		inventory.Warnings["groups"] = err.Error()
+	} else {
+		for _, group := range list.Groups {
+			groups = append(groups, group.Name)
+		}
+	}
+	inventory.CNI = hints(groups, cniGroups)
+	inventory.Ingress = hints(groups, ingressGroups)
+
+	nodes, err := listNodes(ctx, clientset)
+	if err != nil {
+		inventory.Warnings["nodes"] = err.Error()
+	} else {
+		count := len(nodes)
+		inventory.Nodes = &count
+		inventory.Roles = nodeRoles(nodes)
+		inventory.Providers = nodeProviders(nodes)
+		inventory.Regions = nodeRegions(nodes)
+	}
+	inventory.Distributions = distributions(info.GitVersion, nodes, groups)
+
+	if namespaces, err := ListNamespaces(ctx, clientset); err != nil {
+		inventory.Warnings["namespaces"] = err.Error()
+	} else {
+		count := len(namespaces)
+		inventory.Namespaces = &count
+	}
+
+	if len(inventory.Warnings) == 0 {
+		inventory.Warnings = nil
+	}
+	return inventory
+}
+
+func listNodes(ctx context.Context, clientset kubernetes.Interface) ([]corev1.Node, error) {
+	var (
+		nodes []corev1.Node
+		opts  = metav1.ListOptions{Limit: inventoryPageSize}
+	)
+	for {
+		list, err := clientset.CoreV1().Nodes().List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, list.Items...)
+		if len(list.Continue) == 0 {
+			return nodes, nil
+		}
+		opts.Continue = list.Continue
+	}
+}
+
+// hints returns the sorted distinct names of the known groups.
+func hints(groups []string, known map[string]string) []string {
+	var names []string
+	for _, group := range groups {
+		if name, ok := known[group]; ok && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// nodeRoles counts the nodes by the node-role.kubernetes.io/<role> labels,
+// nodes without a role are counted as <none> like kubectl does.
+func nodeRoles(nodes []corev1.Node) map[string]int {
+	roles := map[string]int{}
+	for _, node := range nodes {
+		var found bool
+		for key := range node.Labels {
+			if role, ok := strings.CutPrefix(key, "node-role.kubernetes.io/"); ok && len(role) > 0 {
+				roles[role]++
+				found = true
+			}
+		}
+		if role := node.Labels["kubernetes.io/role"]; !found && len(role) > 0 {
+			roles[role]++
+			found = true
+		}
+		if !found {
+			roles["<none>"]++
+		}
+	}
+	return roles
+}
+
+// nodeProviders infers the cloud providers from the scheme of the node
+// providerIDs, eg. aws:///eu-west-1a/i-0abc.
+func nodeProviders(nodes []corev1.Node) []string {
+	var providers []string
+	for _, node := range nodes {
+		scheme, _, ok := strings.Cut(node.Spec.ProviderID, "://")
+		if !ok || len(scheme) == 0 {
+			continue
+		}
+		provider, ok := providerSchemes[scheme]
+		if !ok {
+			provider = scheme
+		}
+		if !slices.Contains(providers, provider) {
+			providers = append(providers, provider)
+		}
+	}
+	sort.Strings(providers)
+	return providers
+}
+
+func nodeRegions(nodes []corev1.Node) []string {
+	var regions []string
+	for _, node := range nodes {
+		region := node.Labels[corev1.LabelTopologyRegion]
+		if len(region) == 0 {
+			region = node.Labels[corev1.LabelFailureDomainBetaRegion]
+		}
+		if len(region) > 0 && !slices.Contains(regions, region) {
+			regions = append(regions, region)
+		}
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// distributions infers the Kubernetes distributions from the server version,
+// node labels and provider IDs, and the served API groups.
+func distributions(gitVersion string, nodes []corev1.Node, groups []string) []string {
+	var names []string
+	add := func(name string) {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	for marker, name := range versionDistributions {
+		if strings.Contains(gitVersion, marker) {
+			add(name)
+		}
+	}
+	for _, node := range nodes {
+		for key := range node.Labels {
+			if name, ok := labelDistributions[key]; ok {
+				add(name)
+			}
+		}
+		if strings.HasPrefix(node.Spec.ProviderID, "kind://") {
+			add("kind")
+		}
+		if node.Name == "docker-desktop" {
+			add("Docker Desktop")
+		}
+	}
+	if slices.Contains(groups, "config.openshift.io") {
+		add("OpenShift")
+	}
+
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestDistributions(t *testing.T) {
+	node := func(name, providerID string, labels map[string]string) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Spec:       corev1.NodeSpec{ProviderID: providerID},
+		}
+	}
+
+	testdata := []struct {
+		version  string
+		nodes    []corev1.Node
+		groups   []string
+		expected []string
+	}{
+		{"v1.30.4-eks-a737599", nil, nil, []string{"EKS"}},
+		{"v1.30.5-gke.1014001", nil, nil, []string{"GKE"}},
+		{"v1.31.1+k3s1", []corev1.Node{node("n1", "k3s://n1", map[string]string{"k3s.io/hostname": "n1"})}, nil, []string{"k3s"}},
+		{"v1.30.3", []corev1.Node{node("n1", "azure:///subscriptions/x", map[string]string{"kubernetes.azure.com/cluster": "mc"})}, nil, []string{"AKS"}},
+		{"v1.33.1", []corev1.Node{node("kind-control-plane", "kind://docker/kind/kind-control-plane", nil)}, nil, []string{"kind"}},
+		{"v1.29.6+6daa1ed", nil, []string{"apps", "config.openshift.io"}, []string{"OpenShift"}},
+		{"v1.33.1", []corev1.Node{node("n1", "", nil)}, []string{"apps"}, nil},
+	}
+	for _, test := range testdata {
+		if got := distributions(test.version, test.nodes, test.groups); !slices.Equal(got, test.expected) {
+			t.Errorf("distributions() failed, version: %s, expected: %v, got: %v", test.version, test.expected, got)
+		}
+	}
+}
+
+func TestCollectInventory(t *testing.T) {
+	clientset := fake.NewClientset(
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "cp-1", Labels: map[string]string{
+				"node-role.kubernetes.io/control-plane": "",
+				corev1.LabelTopologyRegion:              "eu-west-1",
+			}},
+			Spec: corev1.NodeSpec{ProviderID: "aws:///eu-west-1a/i-0a"},
+		},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-1", Labels: map[string]string{
+				"eks.amazonaws.com/nodegroup": "ng-1",
+				corev1.LabelTopologyRegion:    "eu-west-1",
+			}},
+			Spec: corev1.NodeSpec{ProviderID: "aws:///eu-west-1b/i-0b"},
+		},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "edge-1", Labels: map[string]string{
+				"kubernetes.io/role": "edge",
+			}},
+			Spec: corev1.NodeSpec{ProviderID: "metal://edge-1"},
+		},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+	)
+	clientset.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "v1"},
+		{GroupVersion: "apps/v1"},
+		{GroupVersion: "cilium.io/v2"},
+		{GroupVersion: "networking.istio.io/v1"},
+		{GroupVersion: "gateway.networking.k8s.io/v1"},
+	}
+	info := &version.Info{GitVersion: "v1.30.4-eks-a737599", Platform: "linux/amd64"}
+
+	inventory := collectInventory(context.Background(), clientset, info)
+	if inventory.Version != "v1.30.4-eks-a737599" || inventory.Platform != "linux/amd64" {
+		t.Errorf("collectInventory() failed, expected version: v1.30.4-eks-a737599 linux/amd64, got: %s %s", inventory.Version, inventory.Platform)
+	}
+	if inventory.Nodes == nil || *inventory.Nodes != 3 {
+		t.Errorf("collectInventory() failed, expected nodes: 3, got: %v", inventory.Nodes)
+	}
+	if expected := map[string]int{"control-plane": 1, "edge": 1, "<none>": 1}; !maps.Equal(inventory.Roles, expected) {
+		t.Errorf("collectInventory() failed, expected roles: %v, got: %v", expected, inventory.Roles)
+	}
+	if inventory.Namespaces == nil || *inventory.Namespaces != 2 {
+		t.Errorf("collectInventory() failed, expected namespaces: 2, got: %v", inventory.Namespaces)
+	}
+
+	for _, test := range []struct {
+		name     string
+		got      []string
+		expected []string
+	}{
+		{"distributions", inventory.Distributions, []string{"EKS"}},
+		{"providers", inventory.Providers, []string{"AWS", "metal"}},
+		{"regions", inventory.Regions, []string{"eu-west-1"}},
+		{"cni", inventory.CNI, []string{"Cilium"}},
+		{"ingress", inventory.Ingress, []string{"Gateway API", "Istio"}},
+	} {
+		if !slices.Equal(test.got, test.expected) {
+			t.Errorf("collectInventory() failed, expected %s: %v, got: %v", test.name, test.expected, test.got)
+		}
+	}
+	if inventory.Warnings != nil {
+		t.Errorf("collectInventory() failed, expected no warnings, got: %v", inventory.Warnings)
+	}
+
+	// 无权限列出节点时，其余信息仍然收集
+	clientset.PrependReactor("list", "nodes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "nodes"}, "", nil)
+	})
+	inventory = collectInventory(context.Background(), clientset, info)
+	if inventory.Nodes != nil || inventory.Roles != nil || len(inventory.Warnings["nodes"]) == 0 {
+		t.Errorf("collectInventory() failed, expected nodes warning, got nodes: %v, warnings: %v", inventory.Nodes, inventory.Warnings)
+	}
+	if inventory.Namespaces == nil || !slices.Equal(inventory.Distributions, []string{"EKS"}) {
+		t.Errorf("collectInventory() failed, expected namespaces and distributions without nodes, got: %v %v", inventory.Namespaces, inventory.Distributions)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/ketches/ktx/internal/output"
@@ -29,13 +30,16 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	clientcmdapiv1 "k8s.io/client-go/tools/clientcmd/api/v1"
 	"k8s.io/client-go/util/homedir"
+	"sigs.k8s.io/yaml"
 )
 
 var (
 	DefaultConfigDir  = filepath.Join(homedir.HomeDir(), ".kube")
 	DefaultConfigFile = filepath.Join(DefaultConfigDir, "config")
 	DefaultNamespace  = "default"
+	DefaultStateDir   = filepath.Join(DefaultConfigDir, "ktx")
 )
 
 // NewConfig returns a new kubeconfig
@@ -92,6 +96,57 @@ func SaveConfigToFile(config *clientcmdapi.Config, file string) {
 	}
 }
 
+// MinifyConfig returns a kubeconfig with only the context, its cluster and
+// user, and the context as current. The namespace of the context is replaced
+// if not empty, and relative paths are resolved so that the kubeconfig works
+// from any directory.
+func MinifyConfig(config *clientcmdapi.Config, contextName, namespace string) *clientcmdapi.Config {
+	ctx, ok := config.Contexts[contextName]
+	if !ok {
+		output.Fatal("Context <%s> not found.", contextName)
+	}
+	cluster, ok := config.Clusters[ctx.Cluster]
+	if !ok {
+		output.Fatal("Cluster not found for context <%s>.", contextName)
+	}
+	user, ok := config.AuthInfos[ctx.AuthInfo]
+	if !ok {
+		output.Fatal("User not found for context <%s>.", contextName)
+	}
+
+	minified := NewConfig()
+	minified.CurrentContext = contextName
+	minified.Contexts[contextName] = ctx.DeepCopy()
+	minified.Clusters[ctx.Cluster] = cluster.DeepCopy()
+	minified.AuthInfos[ctx.AuthInfo] = user.DeepCopy()
+	if len(namespace) > 0 {
+		minified.Contexts[contextName].Namespace = namespace
+	}
+	if err := clientcmd.ResolveLocalPaths(minified); err != nil {
+		output.Fatal("Failed to resolve paths of context <%s>: %s", contextName, err)
+	}
+	return minified
+}
+
+// ParseContextNamespace parses "context/namespace" or "context:namespace".
+// The whole argument is the context if it exists, since context names like
+// EKS ARNs contain both separators, otherwise it is split at the last
+// separator whose prefix is an existing context. The namespace is empty if
+// not specified.
+func ParseContextNamespace(config *clientcmdapi.Config, arg string) (string, string) {
+	if _, ok := config.Contexts[arg]; ok {
+		return arg, ""
+	}
+	for _, sep := range []string{"/", ":"} {
+		if i := strings.LastIndex(arg, sep); i > 0 && i < len(arg)-1 {
+			if _, ok := config.Contexts[arg[:i]]; ok {
+				return arg[:i], arg[i+1:]
+			}
+		}
+	}
+	return arg, ""
+}
+
 // PrintConfig prints the kubeconfig
 func PrintConfig(config *clientcmdapi.Config) {
 	v, er := clientcmd.Write(*config)
@@ -101,6 +156,22 @@ func PrintConfig(config *clientcmdapi.Config) {
 	fmt.Print(string(v))
 }
 
+// MarshalUser returns the kubeconfig YAML representation of the user.
+func MarshalUser(user *clientcmdapi.AuthInfo) string {
+	in := user.DeepCopy()
+	in.Extensions = nil
+
+	var out clientcmdapiv1.AuthInfo
+	if err := clientcmdapiv1.Convert_api_AuthInfo_To_v1_AuthInfo(in, &out, nil); err != nil {
+		output.Fatal("Failed to convert user: %s", err)
+	}
+	v, err := yaml.Marshal(out)
+	if err != nil {
+		output.Fatal("Failed to marshal user: %s", err)
+	}
+	return string(v)
+}
+
 // CheckOrInitConfig checks if the kubeconfig exists, if not, create it
 func CheckOrInitConfig() {
 	kubeconfigDir := DefaultConfigDir
@@ -129,7 +200,12 @@ func ListContexts(config *clientcmdapi.Config) []*types.ContextProfile {
 			Cluster:   context.Cluster,
 			User:      context.AuthInfo,
 			Namespace: util.If(len(context.Namespace) > 0, context.Namespace, DefaultNamespace),
-			Server:    config.Clusters[context.Cluster].Server,
+		}
+		if cluster, ok := config.Clusters[context.Cluster]; ok {
+			item.Server = cluster.Server
+		}
+		if user, ok := config.AuthInfos[context.AuthInfo]; ok {
+			item.Impersonate = user.Impersonate
 		}
 		item.Emoji = util.If(item.Current, "✲", " ")
 		contexts = append(contexts, item)
@@ -77,3 +77,36 @@ func TestStandardizeConfig(t *testing.T) {
 		}
 	}
 }
+
+func TestParseContextNamespace(t *testing.T) {
+	config := &clientcmdapi.Config{
+		Contexts: map[string]*clientcmdapi.Context{
+			"prod-eu": {},
+			"arn:aws:eks:eu-west-1:123456789012:cluster/prod": {},
+			"team/dev": {},
+		},
+	}
+
+	testdata := []struct {
+		arg       string
+		context   string
+		namespace string
+	}{
+		{"prod-eu", "prod-eu", ""},
+		{"prod-eu/payments", "prod-eu", "payments"},
+		{"prod-eu:payments", "prod-eu", "payments"},
+		{"prod-eu/", "prod-eu/", ""},
+		{"arn:aws:eks:eu-west-1:123456789012:cluster/prod", "arn:aws:eks:eu-west-1:123456789012:cluster/prod", ""},
+		{"arn:aws:eks:eu-west-1:123456789012:cluster/prod/payments", "arn:aws:eks:eu-west-1:123456789012:cluster/prod", "payments"},
+		{"arn:aws:eks:eu-west-1:123456789012:cluster/prod:payments", "arn:aws:eks:eu-west-1:123456789012:cluster/prod", "payments"},
+		{"team/dev", "team/dev", ""},
+		{"team/dev/apps", "team/dev", "apps"},
+		{"unknown/apps", "unknown/apps", ""},
+	}
+	for _, test := range testdata {
+		context, namespace := ParseContextNamespace(config, test.arg)
+		if context != test.context || namespace != test.namespace {
+			t.Errorf("ParseContextNamespace() failed, arg: %s, expected: %s %s, got: %s %s", test.arg, test.context, test.namespace, context, namespace)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ketches/ktx/internal/util"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	execAPIVersionV1alpha1 = "client.authentication.k8s.io/v1alpha1"
+	execAPIVersionV1beta1  = "client.authentication.k8s.io/v1beta1"
+)
+
+// v1beta1Plugins are exec plugins documented with the v1beta1 exec API only,
+// they are not moved to v1.
+var v1beta1Plugins = []string{"gke-gcloud-auth-plugin", "kubelogin", "kubectl-oidc_login"}
+
+// MigrateUser returns a copy of the user with the deprecated auth-provider or
+// exec API version replaced by a supported exec plugin, and the reasons of
+// the changes. ok is false if nothing deprecated is found.
+func MigrateUser(user *clientcmdapi.AuthInfo) (migrated *clientcmdapi.AuthInfo, reasons []string, ok bool) {
+	migrated = user.DeepCopy()
+
+	if migrated.AuthProvider != nil {
+		exec, reason := authProviderExec(migrated.AuthProvider)
+		if exec != nil {
+			migrated.AuthProvider = nil
+			migrated.Exec = exec
+		}
+		reasons = append(reasons, reason)
+	}
+
+	if migrated.Exec != nil {
+		switch migrated.Exec.APIVersion {
+		case execAPIVersionV1alpha1, execAPIVersionV1beta1:
+			target := ExecAPIVersion
+			if isV1beta1Plugin(migrated.Exec) {
+				target = execAPIVersionV1beta1
+			}
+			if migrated.Exec.APIVersion != target {
+				reasons = append(reasons, fmt.Sprintf("exec apiVersion %s is deprecated, moved to %s", migrated.Exec.APIVersion, target))
+				migrated.Exec.APIVersion = target
+			}
+		}
+
+		// v1 要求显式声明 interactiveMode
+		if migrated.Exec.APIVersion == ExecAPIVersion && len(migrated.Exec.InteractiveMode) == 0 {
+			if user.Exec != nil && user.Exec.APIVersion == ExecAPIVersion {
+				reasons = append(reasons, "exec interactiveMode is required by "+ExecAPIVersion)
+			}
+			migrated.Exec.InteractiveMode = clientcmdapi.IfAvailableExecInteractiveMode
+		}
+	}
+
+	return migrated, reasons, len(reasons) > 0
+}
+
+// authProviderExec returns the exec plugin replacing the auth-provider, exec
+// is nil if the auth-provider has no known replacement.
+func authProviderExec(provider *clientcmdapi.AuthProviderConfig) (exec *clientcmdapi.ExecConfig, reason string) {
+	switch provider.Name {
+	case "gcp":
+		return &clientcmdapi.ExecConfig{
+			APIVersion:         execAPIVersionV1beta1,
+			Command:            "gke-gcloud-auth-plugin",
+			InstallHint:        "Install gke-gcloud-auth-plugin for use with kubectl by following https://cloud.google.com/kubernetes-engine/docs/how-to/cluster-access-for-kubectl#install_plugin",
+			ProvideClusterInfo: true,
+			InteractiveMode:    clientcmdapi.IfAvailableExecInteractiveMode,
+		}, "auth-provider gcp is removed, replaced by gke-gcloud-auth-plugin"
+	case "azure":
+		cfg := provider.Config
+		args := []string{"get-token", "--login", "devicecode",
+			"--environment", util.If(len(cfg["environment"]) > 0, cfg["environment"], "AzurePublicCloud"),
+			"--server-id", cfg["apiserver-id"],
+			"--client-id", cfg["client-id"],
+			"--tenant-id", cfg["tenant-id"],
+		}
+		return &clientcmdapi.ExecConfig{
+			APIVersion:      execAPIVersionV1beta1,
+			Command:         "kubelogin",
+			Args:            args,
+			InstallHint:     "Install kubelogin by following https://azure.github.io/kubelogin/install.html",
+			InteractiveMode: clientcmdapi.IfAvailableExecInteractiveMode,
+		}, "auth-provider azure is removed, replaced by kubelogin"
+	case "oidc":
+		cfg := provider.Config
+		args := []string{"oidc-login", "get-token",
+			"--oidc-issuer-url=" + cfg["idp-issuer-url"],
+			"--oidc-client-id=" + cfg["client-id"],
+		}
+		if len(cfg["client-secret"]) > 0 {
+			args = append(args, "--oidc-client-secret="+cfg["client-secret"])
+		}
+		for _, scope := range strings.Split(cfg["extra-scopes"], ",") {
+			if len(scope) > 0 {
+				args = append(args, "--oidc-extra-scope="+scope)
+			}
+		}
+		if len(cfg["idp-certificate-authority"]) > 0 {
+			args = append(args, "--certificate-authority="+cfg["idp-certificate-authority"])
+		}
+		if len(cfg["idp-certificate-authority-data"]) > 0 {
+			args = append(args, "--certificate-authority-data="+cfg["idp-certificate-authority-data"])
+		}
+		return &clientcmdapi.ExecConfig{
+			APIVersion:      execAPIVersionV1beta1,
+			Command:         "kubectl",
+			Args:            args,
+			InstallHint:     "Install kubelogin by following https://github.com/int128/kubelogin, or run `ktx login` instead",
+			InteractiveMode: clientcmdapi.IfAvailableExecInteractiveMode,
+		}, "auth-provider oidc is removed, replaced by kubelogin (kubectl oidc-login)"
+	default:
+		return nil, fmt.Sprintf("auth-provider %s is removed and has no known replacement, migrate it manually", provider.Name)
+	}
+}
+
+func isV1beta1Plugin(exec *clientcmdapi.ExecConfig) bool {
+	command := filepath.Base(exec.Command)
+	if command == "kubectl" && len(exec.Args) > 0 && exec.Args[0] == "oidc-login" {
+		return true
+	}
+	return slices.Contains(v1beta1Plugins, strings.TrimSuffix(command, ".exe"))
+}
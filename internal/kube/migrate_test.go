@@ -0,0 +1,100 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"testing"
+
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestMigrateUser(t *testing.T) {
+	testdata := []struct {
+		user               *clientcmdapi.AuthInfo
+		ok                 bool
+		expectedCommand    string
+		expectedAPIVersion string
+	}{
+		{
+			user: &clientcmdapi.AuthInfo{Token: "token"},
+			ok:   false,
+		},
+		{
+			user:               &clientcmdapi.AuthInfo{AuthProvider: &clientcmdapi.AuthProviderConfig{Name: "gcp"}},
+			ok:                 true,
+			expectedCommand:    "gke-gcloud-auth-plugin",
+			expectedAPIVersion: execAPIVersionV1beta1,
+		},
+		{
+			user: &clientcmdapi.AuthInfo{AuthProvider: &clientcmdapi.AuthProviderConfig{Name: "azure", Config: map[string]string{
+				"apiserver-id": "server", "client-id": "client", "tenant-id": "tenant",
+			}}},
+			ok:                 true,
+			expectedCommand:    "kubelogin",
+			expectedAPIVersion: execAPIVersionV1beta1,
+		},
+		{
+			user: &clientcmdapi.AuthInfo{AuthProvider: &clientcmdapi.AuthProviderConfig{Name: "oidc", Config: map[string]string{
+				"idp-issuer-url": "https://issuer", "client-id": "client",
+			}}},
+			ok:                 true,
+			expectedCommand:    "kubectl",
+			expectedAPIVersion: execAPIVersionV1beta1,
+		},
+		{
+			user:               &clientcmdapi.AuthInfo{Exec: &clientcmdapi.ExecConfig{Command: "aws", APIVersion: execAPIVersionV1alpha1}},
+			ok:                 true,
+			expectedCommand:    "aws",
+			expectedAPIVersion: ExecAPIVersion,
+		},
+		{
+			user:               &clientcmdapi.AuthInfo{Exec: &clientcmdapi.ExecConfig{Command: "/usr/local/bin/gke-gcloud-auth-plugin", APIVersion: execAPIVersionV1beta1}},
+			ok:                 false,
+			expectedCommand:    "/usr/local/bin/gke-gcloud-auth-plugin",
+			expectedAPIVersion: execAPIVersionV1beta1,
+		},
+		{
+			user:               &clientcmdapi.AuthInfo{Exec: &clientcmdapi.ExecConfig{Command: "aws", APIVersion: ExecAPIVersion, InteractiveMode: clientcmdapi.NeverExecInteractiveMode}},
+			ok:                 false,
+			expectedCommand:    "aws",
+			expectedAPIVersion: ExecAPIVersion,
+		},
+	}
+
+	for _, test := range testdata {
+		migrated, reasons, ok := MigrateUser(test.user)
+		if ok != test.ok {
+			t.Errorf("MigrateUser() failed, user: %s, expected ok: %t, got: %t, reasons: %v", MarshalUser(test.user), test.ok, ok, reasons)
+		}
+		if len(test.expectedCommand) == 0 {
+			continue
+		}
+		if migrated.AuthProvider != nil || migrated.Exec == nil {
+			t.Errorf("MigrateUser() failed, user: %s, expected exec plugin, got: %s", MarshalUser(test.user), MarshalUser(migrated))
+			continue
+		}
+		if migrated.Exec.Command != test.expectedCommand {
+			t.Errorf("MigrateUser() failed, expected command: %s, got: %s", test.expectedCommand, migrated.Exec.Command)
+		}
+		if migrated.Exec.APIVersion != test.expectedAPIVersion {
+			t.Errorf("MigrateUser() failed, expected apiVersion: %s, got: %s", test.expectedAPIVersion, migrated.Exec.APIVersion)
+		}
+		if migrated.Exec.APIVersion == ExecAPIVersion && len(migrated.Exec.InteractiveMode) == 0 {
+			t.Errorf("MigrateUser() failed, expected interactiveMode to be set")
+		}
+	}
+}
@@ -18,21 +18,163 @@ package kube
 
 import (
 	"context"
+	"slices"
+	"sort"
+	"time"
 
-	"github.com/ketches/ktx/internal/output"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
 // ListNamespaces returns a list of namespaces
-func ListNamespaces(kubeClientset kubernetes.Interface) []string {
-	namespaces, err := kubeClientset.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+func ListNamespaces(ctx context.Context, kubeClientset kubernetes.Interface) ([]string, error) {
+	namespaces, err := kubeClientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 	if err != nil {
-		output.Fatal("Failed to list namespaces: %s", err)
+		return nil, err
 	}
 	var ns []string
 	for _, namespace := range namespaces.Items {
 		ns = append(ns, namespace.Name)
 	}
-	return ns
+	return ns, nil
+}
+
+// ListContextNamespaces returns the namespaces of the context, the requests
+// time out after the timeout.
+func ListContextNamespaces(ctx context.Context, kubeConfigFile, contextName string, timeout time.Duration) ([]string, error) {
+	clientset, _, err := timeoutClient(kubeConfigFile, contextName, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return ListNamespaces(ctx, clientset)
+}
+
+// ListContextNamespaceObjects returns the namespace objects of the context,
+// the requests time out after the timeout.
+func ListContextNamespaceObjects(ctx context.Context, kubeConfigFile, contextName string, timeout time.Duration) ([]corev1.Namespace, error) {
+	clientset, _, err := timeoutClient(kubeConfigFile, contextName, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	namespaces, err := clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return namespaces.Items, nil
+}
+
+// DiscoverNamespaces discovers the namespaces the context has access to when
+// it is not allowed to list namespaces. See discoverNamespaces.
+func DiscoverNamespaces(ctx context.Context, kubeConfigFile, contextName, namespace string, timeout time.Duration) ([]string, error) {
+	clientset, restConfig, err := timeoutClient(kubeConfigFile, contextName, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	identity, err := selfSubjectReview(ctx, clientset)
+	if isNotServed(err) {
+		identity, err = localIdentity(restConfig)
+	}
+	if err != nil {
+		// 无法确定身份时仍可以通过访问规则发现
+		identity = nil
+	}
+	return discoverNamespaces(ctx, clientset, identity, namespace), nil
+}
+
+// discoverNamespaces returns the namespaces found without listing namespaces:
+// the namespace if there are rules allowing anything in it, namespaces named
+// in rules allowing to get namespaces, and namespaces of the role bindings
+// readable and bound to the identity, all role bindings readable if the
+// identity is unknown.
+func discoverNamespaces(ctx context.Context, clientset kubernetes.Interface, identity *Identity, namespace string) []string {
+	found := make(map[string]bool)
+
+	if len(namespace) > 0 {
+		review, err := clientset.AuthorizationV1().SelfSubjectRulesReviews().Create(ctx, &authorizationv1.SelfSubjectRulesReview{
+			Spec: authorizationv1.SelfSubjectRulesReviewSpec{Namespace: namespace},
+		}, metav1.CreateOptions{})
+		if err == nil {
+			for _, rule := range review.Status.ResourceRules {
+				if isSelfReviewRule(rule) {
+					continue
+				}
+				found[namespace] = true
+				if matches(rule.APIGroups, "") && matches(rule.Resources, "namespaces") &&
+					(matches(rule.Verbs, "get") || matches(rule.Verbs, "list")) {
+					for _, name := range rule.ResourceNames {
+						found[name] = true
+					}
+				}
+			}
+		}
+	}
+
+	if bindings, err := clientset.RbacV1().RoleBindings(metav1.NamespaceAll).List(ctx, metav1.ListOptions{}); err == nil {
+		for _, binding := range bindings.Items {
+			if identity == nil || boundTo(binding.Subjects, binding.Namespace, identity) {
+				found[binding.Namespace] = true
+			}
+		}
+	}
+
+	namespaces := make([]string, 0, len(found))
+	for name := range found {
+		namespaces = append(namespaces, name)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// isSelfReviewRule reports whether the rule is one granted to every user to
+// review itself, which says nothing about access to the namespace.
+func isSelfReviewRule(rule authorizationv1.ResourceRule) bool {
+	for _, group := range rule.APIGroups {
+		if group != "authorization.k8s.io" && group != "authentication.k8s.io" {
+			return false
+		}
+	}
+	return len(rule.APIGroups) > 0
+}
+
+func matches(values []string, value string) bool {
+	return slices.Contains(values, value) || slices.Contains(values, "*")
+}
+
+// boundTo reports whether the subjects of a binding in the namespace include
+// the identity.
+func boundTo(subjects []rbacv1.Subject, namespace string, identity *Identity) bool {
+	for _, subject := range subjects {
+		switch subject.Kind {
+		case rbacv1.UserKind:
+			if subject.Name == identity.Username {
+				return true
+			}
+		case rbacv1.GroupKind:
+			if slices.Contains(identity.Groups, subject.Name) {
+				return true
+			}
+		case rbacv1.ServiceAccountKind:
+			ns := subject.Namespace
+			if len(ns) == 0 {
+				ns = namespace
+			}
+			if identity.Username == "system:serviceaccount:"+ns+":"+subject.Name {
+				return true
+			}
+		}
+	}
+	return false
 }
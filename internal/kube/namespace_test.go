@@ -0,0 +1,75 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestDiscoverNamespaces(t *testing.T) {
+	binding := func(namespace string, subjects ...rbacv1.Subject) *rbacv1.RoleBinding {
+		return &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "binding", Namespace: namespace},
+			Subjects:   subjects,
+		}
+	}
+	clientset := fake.NewClientset(
+		binding("payments", rbacv1.Subject{Kind: rbacv1.UserKind, Name: "alice"}),
+		binding("orders", rbacv1.Subject{Kind: rbacv1.GroupKind, Name: "team-a"}),
+		binding("ci", rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "deployer"}),
+		binding("platform", rbacv1.Subject{Kind: rbacv1.UserKind, Name: "bob"}),
+	)
+	clientset.PrependReactor("create", "selfsubjectrulesreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectRulesReview)
+		review.Status.ResourceRules = []authorizationv1.ResourceRule{
+			{Verbs: []string{"create"}, APIGroups: []string{"authorization.k8s.io"}, Resources: []string{"selfsubjectaccessreviews"}},
+		}
+		if review.Spec.Namespace == "sandbox" {
+			review.Status.ResourceRules = append(review.Status.ResourceRules,
+				authorizationv1.ResourceRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+				authorizationv1.ResourceRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"namespaces"}, ResourceNames: []string{"shared"}},
+			)
+		}
+		return true, review, nil
+	})
+
+	testdata := []struct {
+		identity  *Identity
+		namespace string
+		expected  []string
+	}{
+		{&Identity{Username: "alice", Groups: []string{"team-a"}}, "sandbox", []string{"orders", "payments", "sandbox", "shared"}},
+		{&Identity{Username: "alice"}, "default", []string{"payments"}},
+		{&Identity{Username: "system:serviceaccount:ci:deployer"}, "", []string{"ci"}},
+		{nil, "", []string{"ci", "orders", "payments", "platform"}},
+	}
+	for _, test := range testdata {
+		got := discoverNamespaces(context.Background(), clientset, test.identity, test.namespace)
+		if !slices.Equal(got, test.expected) {
+			t.Errorf("discoverNamespaces() failed, identity: %v, namespace: %s, expected: %v, got: %v", test.identity, test.namespace, test.expected, got)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ketches/ktx/internal/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+)
+
+// DefaultProbeTimeout is the default timeout of a cluster probe.
+const DefaultProbeTimeout = 2 * time.Second
+
+// ProbeResult is the result of probing the cluster of a context.
+type ProbeResult struct {
+	Status  types.ClusterStatus
+	Version string
+	Latency time.Duration
+	Err     error
+}
+
+// Probe requests the server version of the context cluster, and classifies
+// the failure if any. The request is aborted when ctx is done or the timeout
+// expires.
+func Probe(ctx context.Context, kubeConfigFile, contextName string, timeout time.Duration) *ProbeResult {
+	restConfig, err := config(kubeConfigFile, contextName)
+	if err != nil {
+		return &ProbeResult{Status: ClassifyError(err), Err: err}
+	}
+	restConfig.Timeout = timeout
+
+	dc, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return &ProbeResult{Status: ClassifyError(err), Err: err}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	version, err := serverVersion(ctx, dc)
+	return &ProbeResult{
+		Status:  ClassifyError(err),
+		Version: version,
+		Latency: time.Since(start),
+		Err:     err,
+	}
+}
+
+// serverVersion is ServerVersion honoring the cancellation of ctx.
+func serverVersion(ctx context.Context, dc discovery.DiscoveryInterface) (string, error) {
+	info, err := serverInfo(ctx, dc)
+	if err != nil {
+		return "", err
+	}
+	return info.String(), nil
+}
+
+// serverInfo requests the version info of the server.
+func serverInfo(ctx context.Context, dc discovery.DiscoveryInterface) (*version.Info, error) {
+	body, err := dc.RESTClient().Get().AbsPath("/version").Do(ctx).Raw()
+	if err != nil {
+		return nil, err
+	}
+
+	var info version.Info
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("unable to parse the server version: %w", err)
+	}
+	return &info, nil
+}
+
+// ClassifyError classifies the error of a request to the cluster.
+func ClassifyError(err error) types.ClusterStatus {
+	if err == nil {
+		return types.ClusterStatusAvailable
+	}
+
+	var (
+		msg              = err.Error()
+		dnsErr           *net.DNSError
+		opErr            *net.OpError
+		unknownAuthority x509.UnknownAuthorityError
+		hostnameErr      x509.HostnameError
+		invalidCert      x509.CertificateInvalidError
+		verificationErr  *tls.CertificateVerificationError
+		recordHeaderErr  tls.RecordHeaderError
+		netErr           net.Error
+	)
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return types.ClusterStatusCanceled
+	case strings.Contains(msg, "exec: executable") && strings.Contains(msg, "not found"):
+		return types.ClusterStatusExecPluginMissing
+	case apierrors.IsUnauthorized(err):
+		return types.ClusterStatusUnauthenticated
+	case apierrors.IsForbidden(err):
+		return types.ClusterStatusForbidden
+	case errors.As(err, &opErr) && opErr.Op == "proxyconnect", strings.Contains(msg, "proxyconnect"):
+		return types.ClusterStatusProxyError
+	case errors.As(err, &dnsErr):
+		return types.ClusterStatusDNSError
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return types.ClusterStatusConnectionRefused
+	case errors.As(err, &invalidCert) && invalidCert.Reason == x509.Expired,
+		strings.Contains(msg, "certificate has expired"),
+		strings.Contains(msg, "tls: expired certificate"):
+		return types.ClusterStatusCertificateExpired
+	case errors.As(err, &unknownAuthority),
+		errors.As(err, &hostnameErr),
+		errors.As(err, &invalidCert),
+		errors.As(err, &verificationErr),
+		errors.As(err, &recordHeaderErr),
+		strings.Contains(msg, "x509:"),
+		strings.Contains(msg, "tls:"):
+		return types.ClusterStatusTLSError
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout(),
+		strings.Contains(msg, "Client.Timeout exceeded"):
+		return types.ClusterStatusTimeout
+	default:
+		return types.ClusterStatusUnavailable
+	}
+}
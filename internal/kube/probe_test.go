@@ -0,0 +1,123 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ketches/ktx/internal/types"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestClassifyError(t *testing.T) {
+	testdata := []struct {
+		err      error
+		expected types.ClusterStatus
+	}{
+		{nil, types.ClusterStatusAvailable},
+		{&net.DNSError{Err: "no such host", Name: "api.example.invalid"}, types.ClusterStatusDNSError},
+		{fmt.Errorf("getting credentials: exec: executable aws not found"), types.ClusterStatusExecPluginMissing},
+		{&net.OpError{Op: "proxyconnect", Net: "tcp", Err: fmt.Errorf("dial tcp: connection refused")}, types.ClusterStatusProxyError},
+		{fmt.Errorf("Get \"https://1.2.3.4/version\": x509: certificate has expired or is not yet valid"), types.ClusterStatusCertificateExpired},
+		{fmt.Errorf("Get \"https://1.2.3.4/version\": remote error: tls: expired certificate"), types.ClusterStatusCertificateExpired},
+		{fmt.Errorf("probe: %w", context.Canceled), types.ClusterStatusCanceled},
+		{fmt.Errorf("something else"), types.ClusterStatusUnavailable},
+	}
+
+	for _, test := range testdata {
+		if got := ClassifyError(test.err); got != test.expected {
+			t.Errorf("ClassifyError() failed, error: %v, expected: %s, got: %s", test.err, test.expected, got)
+		}
+	}
+}
+
+func TestProbe(t *testing.T) {
+	handler := func(status int, body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			fmt.Fprint(w, body)
+		}
+	}
+
+	available := httptest.NewServer(handler(http.StatusOK, `{"major":"1","minor":"33","gitVersion":"v1.33.1"}`))
+	defer available.Close()
+	unauthorized := httptest.NewServer(handler(http.StatusUnauthorized, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Unauthorized","code":401}`))
+	defer unauthorized.Close()
+	forbidden := httptest.NewServer(handler(http.StatusForbidden, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Forbidden","code":403}`))
+	defer forbidden.Close()
+	untrusted := httptest.NewTLSServer(handler(http.StatusOK, `{}`))
+	defer untrusted.Close()
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(2 * time.Second)
+	}))
+	defer slow.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	refused := "http://" + listener.Addr().String()
+	listener.Close()
+
+	servers := map[string]string{
+		"available":    available.URL,
+		"unauthorized": unauthorized.URL,
+		"forbidden":    forbidden.URL,
+		"untrusted":    untrusted.URL,
+		"slow":         slow.URL,
+		"refused":      refused,
+	}
+	config := NewConfig()
+	for name, server := range servers {
+		config.Clusters["cluster-"+name] = &clientcmdapi.Cluster{Server: server}
+		config.AuthInfos["user-"+name] = &clientcmdapi.AuthInfo{Token: "token"}
+		config.Contexts[name] = &clientcmdapi.Context{Cluster: "cluster-" + name, AuthInfo: "user-" + name}
+	}
+	file := filepath.Join(t.TempDir(), "config")
+	if err := clientcmd.WriteToFile(*config, file); err != nil {
+		t.Fatal(err)
+	}
+
+	testdata := map[string]types.ClusterStatus{
+		"available":    types.ClusterStatusAvailable,
+		"unauthorized": types.ClusterStatusUnauthenticated,
+		"forbidden":    types.ClusterStatusForbidden,
+		"untrusted":    types.ClusterStatusTLSError,
+		"slow":         types.ClusterStatusTimeout,
+		"refused":      types.ClusterStatusConnectionRefused,
+		"missing":      types.ClusterStatusUnavailable,
+	}
+	for name, expected := range testdata {
+		result := Probe(context.Background(), file, name, 500*time.Millisecond)
+		if result.Status != expected {
+			t.Errorf("Probe() failed, context: %s, expected: %s, got: %s, error: %v", name, expected, result.Status, result.Err)
+		}
+	}
+
+	if result := Probe(context.Background(), file, "available", time.Second); result.Version != "v1.33.1" {
+		t.Errorf("Probe() failed, expected version: %s, got: %s", "v1.33.1", result.Version)
+	}
+}
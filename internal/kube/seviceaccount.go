@@ -18,6 +18,7 @@ package kube
 
 import (
 	"context"
+	"time"
 
 	"github.com/ketches/ktx/internal/output"
 	v1 "k8s.io/api/core/v1"
@@ -35,14 +36,27 @@ func GetServiceAccount(kubeClientset kubernetes.Interface, serviceAccountName, n
 }
 
 // ListServiceAccounts returns a list of service accounts
-func ListServiceAccounts(kubeClientset kubernetes.Interface, namespace string) []string {
-	serviceAccounts, err := kubeClientset.CoreV1().ServiceAccounts(namespace).List(context.Background(), metav1.ListOptions{})
+func ListServiceAccounts(ctx context.Context, kubeClientset kubernetes.Interface, namespace string) ([]string, error) {
+	serviceAccounts, err := kubeClientset.CoreV1().ServiceAccounts(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		output.Fatal("Failed to list service accounts: %s", err)
+		return nil, err
 	}
 	var sa []string
 	for _, serviceAccount := range serviceAccounts.Items {
 		sa = append(sa, serviceAccount.Name)
 	}
-	return sa
+	return sa, nil
+}
+
+// ListContextServiceAccounts returns the service accounts in the namespace of
+// the context, the requests time out after the timeout.
+func ListContextServiceAccounts(ctx context.Context, kubeConfigFile, contextName, namespace string, timeout time.Duration) ([]string, error) {
+	clientset, _, err := timeoutClient(kubeConfigFile, contextName, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return ListServiceAccounts(ctx, clientset, namespace)
 }
@@ -0,0 +1,101 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+)
+
+// MaxSkew is the number of minor versions kubectl is supported to be older
+// or newer than kube-apiserver by the Kubernetes version skew policy.
+const MaxSkew = 1
+
+// Skew is the version skew between a client and a server.
+type Skew struct {
+	Client string `json:"client"`
+	Server string `json:"server"`
+	// Minors is the number of minor versions the client is newer than the
+	// server, negative if older.
+	Minors    int  `json:"minors"`
+	Supported bool `json:"supported"`
+}
+
+// CheckSkew checks the skew between the client and server versions, eg.
+// v1.33.1 and v1.30.4-eks-a737599.
+func CheckSkew(client, server string) (*Skew, error) {
+	clientVersion, err := utilversion.ParseGeneric(client)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client version %q: %w", client, err)
+	}
+	serverVersion, err := utilversion.ParseGeneric(server)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server version %q: %w", server, err)
+	}
+
+	skew := &Skew{Client: client, Server: server}
+	if clientVersion.Major() != serverVersion.Major() {
+		return skew, nil
+	}
+	skew.Minors = int(clientVersion.Minor()) - int(serverVersion.Minor())
+	skew.Supported = skew.Minors >= -MaxSkew && skew.Minors <= MaxSkew
+	return skew, nil
+}
+
+// String describes the skew, eg. "2 minor versions older".
+func (s *Skew) String() string {
+	switch {
+	case !s.Supported && s.Minors == 0:
+		return "different major version"
+	case s.Minors == 0:
+		return "same minor version"
+	case s.Minors == 1:
+		return "1 minor version newer"
+	case s.Minors == -1:
+		return "1 minor version older"
+	case s.Minors > 0:
+		return fmt.Sprintf("%d minor versions newer", s.Minors)
+	default:
+		return fmt.Sprintf("%d minor versions older", -s.Minors)
+	}
+}
+
+// ClientGoVersion returns the Kubernetes version of the client-go built into
+// ktx, eg. v1.33.1 for k8s.io/client-go v0.33.1, or "" if unknown.
+func ClientGoVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == "k8s.io/client-go" {
+			return clientGoKubernetesVersion(dep.Version)
+		}
+	}
+	return ""
+}
+
+// clientGoKubernetesVersion maps client-go v0.x.y to Kubernetes v1.x.y.
+func clientGoKubernetesVersion(version string) string {
+	if rest, ok := strings.CutPrefix(version, "v0."); ok {
+		return "v1." + rest
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import "testing"
+
+func TestCheckSkew(t *testing.T) {
+	testdata := []struct {
+		client    string
+		server    string
+		minors    int
+		supported bool
+		expected  string
+	}{
+		{"v1.33.1", "v1.33.0", 0, true, "same minor version"},
+		{"v1.33.1", "v1.32.4-eks-a737599", 1, true, "1 minor version newer"},
+		{"v1.31.0", "v1.32.5-gke.1014001", -1, true, "1 minor version older"},
+		{"v1.33.1", "v1.30.4+k3s1", 3, false, "3 minor versions newer"},
+		{"v1.28.2", "v1.31.0", -3, false, "3 minor versions older"},
+		{"v2.0.0", "v1.31.0", 0, false, "different major version"},
+	}
+	for _, test := range testdata {
+		skew, err := CheckSkew(test.client, test.server)
+		if err != nil {
+			t.Errorf("CheckSkew() failed, client: %s, server: %s, error: %s", test.client, test.server, err)
+			continue
+		}
+		if skew.Minors != test.minors || skew.Supported != test.supported || skew.String() != test.expected {
+			t.Errorf("CheckSkew() failed, client: %s, server: %s, expected: %d %t %q, got: %d %t %q",
+				test.client, test.server, test.minors, test.supported, test.expected, skew.Minors, skew.Supported, skew.String())
+		}
+	}
+
+	if _, err := CheckSkew("unknown", "v1.33.0"); err == nil {
+		t.Errorf("CheckSkew() failed, expected error of invalid client version")
+	}
+}
+
+func TestClientGoKubernetesVersion(t *testing.T) {
+	testdata := map[string]string{
+		"v0.33.1":         "v1.33.1",
+		"v0.30.0-alpha.1": "v1.30.0-alpha.1",
+		"(devel)":         "",
+	}
+	for version, expected := range testdata {
+		if got := clientGoKubernetesVersion(version); got != expected {
+			t.Errorf("clientGoKubernetesVersion() failed, version: %s, expected: %q, got: %q", version, expected, got)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ketches/ktx/internal/util"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authenticationv1beta1 "k8s.io/api/authentication/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	IdentitySourceReview            = "SelfSubjectReview"
+	IdentitySourceToken             = "token"
+	IdentitySourceClientCertificate = "client-certificate"
+	IdentitySourceImpersonate       = "impersonate"
+)
+
+// Identity is the user a context authenticates as.
+type Identity struct {
+	Username string              `json:"username"`
+	UID      string              `json:"uid,omitempty"`
+	Groups   []string            `json:"groups,omitempty"`
+	Extra    map[string][]string `json:"extra,omitempty"`
+	// Source is SelfSubjectReview, or the credential decoded locally when the
+	// server does not serve SelfSubjectReview.
+	Source string `json:"source"`
+}
+
+// WhoAmI returns the identity the context authenticates as, requested with
+// SelfSubjectReview, and decoded locally from the credential on servers
+// older than 1.27.
+func WhoAmI(ctx context.Context, kubeConfigFile, contextName string, timeout time.Duration) (*Identity, error) {
+	clientset, restConfig, err := timeoutClient(kubeConfigFile, contextName, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	identity, err := selfSubjectReview(ctx, clientset)
+	if isNotServed(err) {
+		identity, err = localIdentity(restConfig)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+func selfSubjectReview(ctx context.Context, clientset kubernetes.Interface) (*Identity, error) {
+	review, err := clientset.AuthenticationV1().SelfSubjectReviews().Create(ctx, &authenticationv1.SelfSubjectReview{}, metav1.CreateOptions{})
+	if err == nil {
+		return reviewIdentity(review.Status.UserInfo), nil
+	}
+	if !isNotServed(err) {
+		return nil, err
+	}
+
+	// 1.27 仅提供 v1beta1
+	reviewBeta, err := clientset.AuthenticationV1beta1().SelfSubjectReviews().Create(ctx, &authenticationv1beta1.SelfSubjectReview{}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return reviewIdentity(reviewBeta.Status.UserInfo), nil
+}
+
+func reviewIdentity(user authenticationv1.UserInfo) *Identity {
+	identity := &Identity{
+		Username: user.Username,
+		UID:      user.UID,
+		Groups:   user.Groups,
+		Source:   IdentitySourceReview,
+	}
+	if len(user.Extra) > 0 {
+		identity.Extra = make(map[string][]string, len(user.Extra))
+		for k, v := range user.Extra {
+			identity.Extra[k] = v
+		}
+	}
+	return identity
+}
+
+func isNotServed(err error) bool {
+	return apierrors.IsNotFound(err) || apierrors.IsMethodNotSupported(err)
+}
+
+// localIdentity decodes the identity from the impersonation settings, the
+// JWT bearer token or the client certificate of the rest config.
+func localIdentity(restConfig *rest.Config) (*Identity, error) {
+	if len(restConfig.Impersonate.UserName) > 0 {
+		return &Identity{
+			Username: restConfig.Impersonate.UserName,
+			UID:      restConfig.Impersonate.UID,
+			Groups:   restConfig.Impersonate.Groups,
+			Extra:    restConfig.Impersonate.Extra,
+			Source:   IdentitySourceImpersonate,
+		}, nil
+	}
+
+	token := restConfig.BearerToken
+	if len(token) == 0 && len(restConfig.BearerTokenFile) > 0 {
+		data, err := os.ReadFile(restConfig.BearerTokenFile)
+		if err != nil {
+			return nil, err
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	if len(token) > 0 {
+		return tokenIdentity(token)
+	}
+
+	cert, err := loadData(restConfig.CertData, restConfig.CertFile, "")
+	if err != nil {
+		return nil, err
+	}
+	if cert != nil {
+		certs, err := ParseCertificates(cert)
+		if err != nil {
+			return nil, err
+		}
+		return &Identity{
+			Username: certs[0].Subject.CommonName,
+			Groups:   certs[0].Subject.Organization,
+			Source:   IdentitySourceClientCertificate,
+		}, nil
+	}
+
+	return nil, errors.New("SelfSubjectReview is not served and the credential can not be decoded locally")
+}
+
+// tokenIdentity decodes the identity from the claims of a JWT, service
+// account tokens get the groups assigned by the service account
+// authenticator.
+func tokenIdentity(token string) (*Identity, error) {
+	claims, err := util.JWTClaims(token)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &Identity{Source: IdentitySourceToken}
+	identity.Username, _ = claims["sub"].(string)
+	if groups, ok := claims["groups"].([]any); ok {
+		for _, group := range groups {
+			if s, ok := group.(string); ok {
+				identity.Groups = append(identity.Groups, s)
+			}
+		}
+	}
+
+	if parts := strings.Split(identity.Username, ":"); len(parts) == 4 && parts[0] == "system" && parts[1] == "serviceaccount" {
+		identity.Groups = []string{"system:serviceaccounts", "system:serviceaccounts:" + parts[2], "system:authenticated"}
+		if k8s, ok := claims["kubernetes.io"].(map[string]any); ok {
+			if sa, ok := k8s["serviceaccount"].(map[string]any); ok {
+				identity.UID, _ = sa["uid"].(string)
+			}
+		}
+		if uid, ok := claims["kubernetes.io/serviceaccount/service-account.uid"].(string); ok {
+			identity.UID = uid
+		}
+	}
+	if len(identity.Username) == 0 {
+		return nil, errors.New("token has no sub claim")
+	}
+	return identity, nil
+}
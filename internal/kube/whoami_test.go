@@ -0,0 +1,80 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestWhoAmI(t *testing.T) {
+	review := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/apis/authentication.k8s.io/v1/selfsubjectreviews" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"kind":"SelfSubjectReview","apiVersion":"authentication.k8s.io/v1","status":{"userInfo":{"username":"alice","uid":"1","groups":["dev","system:authenticated"],"extra":{"scopes":["a","b"]}}}}`)
+	}))
+	defer review.Close()
+	legacy := httptest.NewTLSServer(http.NotFoundHandler())
+	defer legacy.Close()
+
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"system:serviceaccount:kube-system:deployer","kubernetes.io":{"serviceaccount":{"uid":"2"}}}`))
+	config := NewConfig()
+	config.Clusters["review"] = &clientcmdapi.Cluster{Server: review.URL, InsecureSkipTLSVerify: true}
+	config.Clusters["legacy"] = &clientcmdapi.Cluster{Server: legacy.URL, InsecureSkipTLSVerify: true}
+	config.AuthInfos["token"] = &clientcmdapi.AuthInfo{Token: "header." + payload + ".signature"}
+	config.AuthInfos["impersonate"] = &clientcmdapi.AuthInfo{Token: "opaque", Impersonate: "bob", ImpersonateGroups: []string{"ops"}}
+	config.Contexts["review"] = &clientcmdapi.Context{Cluster: "review", AuthInfo: "token"}
+	config.Contexts["legacy-token"] = &clientcmdapi.Context{Cluster: "legacy", AuthInfo: "token"}
+	config.Contexts["legacy-impersonate"] = &clientcmdapi.Context{Cluster: "legacy", AuthInfo: "impersonate"}
+	file := filepath.Join(t.TempDir(), "config")
+	if err := clientcmd.WriteToFile(*config, file); err != nil {
+		t.Fatal(err)
+	}
+
+	testdata := map[string]*Identity{
+		"review":             {Username: "alice", UID: "1", Groups: []string{"dev", "system:authenticated"}, Source: IdentitySourceReview},
+		"legacy-token":       {Username: "system:serviceaccount:kube-system:deployer", UID: "2", Groups: []string{"system:serviceaccounts", "system:serviceaccounts:kube-system", "system:authenticated"}, Source: IdentitySourceToken},
+		"legacy-impersonate": {Username: "bob", Groups: []string{"ops"}, Source: IdentitySourceImpersonate},
+	}
+	for name, expected := range testdata {
+		got, err := WhoAmI(context.Background(), file, name, time.Second)
+		if err != nil {
+			t.Errorf("WhoAmI() failed, context: %s, error: %s", name, err)
+			continue
+		}
+		if got.Username != expected.Username || got.UID != expected.UID || !slices.Equal(got.Groups, expected.Groups) || got.Source != expected.Source {
+			t.Errorf("WhoAmI() failed, context: %s, expected: %+v, got: %+v", name, expected, got)
+		}
+	}
+
+	if got, err := WhoAmI(context.Background(), file, "review", time.Second); err != nil || len(got.Extra["scopes"]) != 2 {
+		t.Errorf("WhoAmI() failed, expected extra scopes, got: %+v, error: %v", got, err)
+	}
+}
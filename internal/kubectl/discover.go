@@ -0,0 +1,109 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectl
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+
+	"github.com/ketches/ktx/internal/cache"
+)
+
+// binaryPattern matches the names of kubectl binaries, eg. kubectl,
+// kubectl-1.29, kubectl1.29.3 or kubectl-v1.30, but not kubectl plugins such
+// as kubectl-krew.
+var binaryPattern = regexp.MustCompile(`^kubectl(?:[-_]?v?\d+\.\d+(?:\.\d+)?)?(?:\.exe)?$`)
+
+// Binary is an installed kubectl binary.
+type Binary struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+}
+
+// Dirs returns the directories searched for kubectl binaries: $PATH, ~/bin and
+// ~/.local/bin.
+func Dirs() []string {
+	dirs := filepath.SplitList(os.Getenv("PATH"))
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, "bin"), filepath.Join(home, ".local", "bin"))
+	}
+	return dirs
+}
+
+// Discover finds the kubectl binaries in the directories, and their versions.
+// Nothing is downloaded, binaries are only run to get their versions, which
+// are cached until the binaries change. Binaries linked to the same file are
+// listed once, and binaries failing to report their versions are skipped.
+func Discover(ctx context.Context, dirs []string) []Binary {
+	var (
+		binaries []Binary
+		seen     []string
+	)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !binaryPattern.MatchString(entry.Name()) {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			target, err := filepath.EvalSymlinks(path)
+			if err != nil || slices.Contains(seen, target) {
+				continue
+			}
+			seen = append(seen, target)
+
+			version, err := cachedVersion(ctx, path, target)
+			if err != nil {
+				continue
+			}
+			binaries = append(binaries, Binary{Path: path, Version: version})
+		}
+	}
+	return binaries
+}
+
+// cachedVersion returns the version of the binary, cached by the modification
+// time and size of the file it links to.
+func cachedVersion(ctx context.Context, path, target string) (string, error) {
+	info, err := os.Stat(target)
+	if err != nil {
+		return "", err
+	}
+	if info.Mode()&0o111 == 0 && filepath.Ext(target) != ".exe" {
+		return "", fmt.Errorf("%s is not executable", path)
+	}
+
+	key := fmt.Sprintf("%s@%d:%d", target, info.ModTime().UnixNano(), info.Size())
+	if entry, err := cache.Get(cache.KindKubectl, key); err == nil && entry != nil && len(entry.Items) == 1 {
+		return entry.Items[0], nil
+	}
+
+	version, err := Version(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	// 缓存失败不影响结果
+	_ = cache.Put(cache.KindKubectl, key, []string{version})
+	return version, nil
+}
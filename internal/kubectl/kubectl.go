@@ -0,0 +1,96 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Name is the name of the kubectl executable.
+const Name = "kubectl"
+
+// versionTimeout is the timeout of asking a kubectl binary for its version.
+const versionTimeout = 5 * time.Second
+
+// Version returns the client version of the kubectl binary, eg. v1.33.1.
+// Only the binary is run, no request is sent to any cluster.
+func Version(ctx context.Context, path string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, versionTimeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, "version", "--client", "--output", "json")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); len(msg) > 0 {
+			return "", fmt.Errorf("%s version: %w: %s", path, err, msg)
+		}
+		return "", fmt.Errorf("%s version: %w", path, err)
+	}
+
+	var info struct {
+		ClientVersion struct {
+			GitVersion string `json:"gitVersion"`
+		} `json:"clientVersion"`
+	}
+	if err := json.Unmarshal(out, &info); err != nil {
+		return "", fmt.Errorf("unable to parse the version of %s: %w", path, err)
+	}
+	if len(info.ClientVersion.GitVersion) == 0 {
+		return "", fmt.Errorf("no client version reported by %s", path)
+	}
+	return info.ClientVersion.GitVersion, nil
+}
+
+// Lookup returns the path of kubectl in $PATH.
+func Lookup() (string, error) {
+	return exec.LookPath(Name)
+}
+
+// ParseTarget returns the kubeconfig and context specified in the kubectl
+// arguments by --kubeconfig and --context, empty if not specified.
+func ParseTarget(args []string) (kubeConfigFile, contextName string) {
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		if name == "--" {
+			break
+		}
+		if name != "--kubeconfig" && name != "--context" {
+			continue
+		}
+		if !hasValue {
+			if i+1 == len(args) {
+				break
+			}
+			i++
+			value = args[i]
+		}
+		if name == "--kubeconfig" {
+			kubeConfigFile = value
+		} else {
+			contextName = value
+		}
+	}
+	return kubeConfigFile, contextName
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/ketches/ktx/internal/cache"
+)
+
+// fakeKubectl writes a script printing the output as kubectl.
+func fakeKubectl(t *testing.T, dir, name, output string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	script := "#!/bin/sh\ncat <<'EOF'\n" + output + "\nEOF\n"
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestVersion(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not executable on windows")
+	}
+	dir := t.TempDir()
+
+	testdata := []struct {
+		output   string
+		expected string
+	}{
+		{`{"clientVersion":{"major":"1","minor":"33","gitVersion":"v1.33.1"},"kustomizeVersion":"v5.6.0"}`, "v1.33.1"},
+		{`{"kustomizeVersion":"v5.6.0"}`, ""},
+		{`Client Version: v1.33.1`, ""},
+	}
+	for i, test := range testdata {
+		path := fakeKubectl(t, dir, "kubectl-"+string(rune('a'+i)), test.output)
+		got, err := Version(context.Background(), path)
+		if (err != nil) != (test.expected == "") || got != test.expected {
+			t.Errorf("Version() failed, output: %s, expected: %q, got: %q, error: %v", test.output, test.expected, got, err)
+		}
+	}
+
+	if _, err := Version(context.Background(), filepath.Join(dir, "missing")); err == nil {
+		t.Errorf("Version() failed, expected error of missing binary")
+	}
+}
+
+func TestParseTarget(t *testing.T) {
+	testdata := []struct {
+		args       []string
+		kubeconfig string
+		context    string
+	}{
+		{[]string{"get", "pods"}, "", ""},
+		{[]string{"--context", "prod", "get", "pods"}, "", "prod"},
+		{[]string{"get", "pods", "--context=prod", "--kubeconfig=/tmp/config"}, "/tmp/config", "prod"},
+		{[]string{"--kubeconfig", "/tmp/config", "get", "pods"}, "/tmp/config", ""},
+		{[]string{"exec", "pod", "--", "kubectl", "--context", "other"}, "", ""},
+		{[]string{"get", "pods", "--context"}, "", ""},
+	}
+	for _, test := range testdata {
+		kubeconfig, context := ParseTarget(test.args)
+		if kubeconfig != test.kubeconfig || context != test.context {
+			t.Errorf("ParseTarget() failed, args: %v, expected: %q %q, got: %q %q", test.args, test.kubeconfig, test.context, kubeconfig, context)
+		}
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not executable on windows")
+	}
+	cache.Dir = filepath.Join(t.TempDir(), "cache")
+	dir1, dir2 := t.TempDir(), t.TempDir()
+
+	version := func(v string) string {
+		return `{"clientVersion":{"gitVersion":"` + v + `"}}`
+	}
+	fakeKubectl(t, dir1, "kubectl", version("v1.33.1"))
+	fakeKubectl(t, dir2, "kubectl-1.29", version("v1.29.3"))
+	fakeKubectl(t, dir2, "kubectl-krew", version("v0.4.4"))
+	fakeKubectl(t, dir2, "kubectl-broken", "broken")
+	fakeKubectl(t, dir2, "kubectl1.28", "broken")
+	if err := os.Symlink(filepath.Join(dir1, "kubectl"), filepath.Join(dir2, "kubectl")); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Binary{
+		{Path: filepath.Join(dir1, "kubectl"), Version: "v1.33.1"},
+		{Path: filepath.Join(dir2, "kubectl-1.29"), Version: "v1.29.3"},
+	}
+	for i := 0; i < 2; i++ {
+		if got := Discover(context.Background(), []string{dir1, dir2, filepath.Join(dir2, "missing")}); !slices.Equal(got, expected) {
+			t.Errorf("Discover() failed, round: %d, expected: %v, got: %v", i, expected, got)
+		}
+	}
+
+	// 可执行文件变化后重新获取版本
+	if err := os.WriteFile(filepath.Join(dir1, "kubectl"), nil, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if got := Discover(context.Background(), []string{dir1}); len(got) != 0 {
+		t.Errorf("Discover() failed, expected changed binary to be run again, got: %v", got)
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ketches/ktx/internal/kube"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+)
+
+// Rule maps the server versions in a range to a kubectl binary.
+type Rule struct {
+	// Versions is the range of server minor versions, eg. 1.29, 1.27-1.29,
+	// 1.30- or -1.26, inclusive.
+	Versions string `json:"versions"`
+	Path     string `json:"path"`
+}
+
+// Range is a range of minor versions, unbounded if nil.
+type Range struct {
+	Min, Max *utilversion.Version
+}
+
+// ParseRange parses a range of minor versions, eg. 1.29, 1.27-1.29, 1.30- or
+// -1.26.
+func ParseRange(s string) (*Range, error) {
+	parse := func(v string) (*utilversion.Version, error) {
+		if len(v) == 0 {
+			return nil, nil
+		}
+		version, err := utilversion.ParseGeneric(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version range %q: %w", s, err)
+		}
+		return version, nil
+	}
+
+	minVersion, maxVersion, found := strings.Cut(strings.TrimSpace(s), "-")
+	if !found {
+		maxVersion = minVersion
+	}
+	if len(minVersion) == 0 && len(maxVersion) == 0 {
+		return nil, fmt.Errorf("invalid version range %q", s)
+	}
+
+	var (
+		r   = &Range{}
+		err error
+	)
+	if r.Min, err = parse(minVersion); err != nil {
+		return nil, err
+	}
+	if r.Max, err = parse(maxVersion); err != nil {
+		return nil, err
+	}
+	if r.Min != nil && r.Max != nil && compareMinor(r.Min, r.Max) > 0 {
+		return nil, fmt.Errorf("invalid version range %q: %s is greater than %s", s, minVersion, maxVersion)
+	}
+	return r, nil
+}
+
+// Contains reports whether the minor version of the version is in the range.
+func (r *Range) Contains(version *utilversion.Version) bool {
+	return (r.Min == nil || compareMinor(version, r.Min) >= 0) &&
+		(r.Max == nil || compareMinor(version, r.Max) <= 0)
+}
+
+// compareMinor compares the major and minor versions only.
+func compareMinor(a, b *utilversion.Version) int {
+	if a.Major() != b.Major() {
+		return int(a.Major()) - int(b.Major())
+	}
+	return int(a.Minor()) - int(b.Minor())
+}
+
+// Resolution is the kubectl binary resolved for a context.
+type Resolution struct {
+	Path   string
+	Reason string
+}
+
+// Resolve resolves the kubectl binary for a context: the binary mapped to the
+// context, the first rule matching the server version, or the installed
+// binary closest to the server version within the supported skew. The
+// installed binaries are only discovered if no mapping or rule matches. nil
+// is returned if none, the server version is empty if unknown.
+func Resolve(contextPath string, rules []Rule, server string, discover func() []Binary) *Resolution {
+	if len(contextPath) > 0 {
+		return &Resolution{Path: contextPath, Reason: "mapped to the context"}
+	}
+
+	serverVersion, err := utilversion.ParseGeneric(server)
+	if err != nil {
+		return nil
+	}
+	for _, rule := range rules {
+		r, err := ParseRange(rule.Versions)
+		if err == nil && r.Contains(serverVersion) {
+			return &Resolution{Path: rule.Path, Reason: "server " + server + " matches versions " + rule.Versions}
+		}
+	}
+	if binary := Select(discover(), serverVersion); binary != nil {
+		return &Resolution{Path: binary.Path, Reason: "installed kubectl closest to server " + server}
+	}
+	return nil
+}
+
+// Select selects the binary closest to the server version within the
+// supported skew, preferring the same minor version, then one newer, then one
+// older, and the latest patch. nil is returned if none is within the skew.
+func Select(binaries []Binary, server *utilversion.Version) *Binary {
+	type candidate struct {
+		binary  *Binary
+		version *utilversion.Version
+		rank    int
+	}
+
+	var candidates []candidate
+	for i := range binaries {
+		version, err := utilversion.ParseGeneric(binaries[i].Version)
+		if err != nil || version.Major() != server.Major() {
+			continue
+		}
+		skew := int(version.Minor()) - int(server.Minor())
+		if skew < -kube.MaxSkew || skew > kube.MaxSkew {
+			continue
+		}
+		// 同一次版本排在最前，其次新一个次版本，旧一个次版本排在最后
+		rank := skew
+		if skew < 0 {
+			rank = 2
+		}
+		candidates = append(candidates, candidate{&binaries[i], version, rank})
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].rank != candidates[j].rank {
+			return candidates[i].rank < candidates[j].rank
+		}
+		return candidates[j].version.LessThan(candidates[i].version)
+	})
+	return candidates[0].binary
+}
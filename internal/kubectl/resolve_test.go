@@ -0,0 +1,108 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectl
+
+import (
+	"testing"
+
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+)
+
+func TestParseRange(t *testing.T) {
+	testdata := []struct {
+		versions string
+		valid    bool
+		contains []string
+		excludes []string
+	}{
+		{"1.29", true, []string{"v1.29.0", "v1.29.12-eks-a737599"}, []string{"v1.28.9", "v1.30.0"}},
+		{"1.27-1.29", true, []string{"v1.27.1", "v1.28.0", "v1.29.9"}, []string{"v1.26.0", "v1.30.0"}},
+		{"v1.30-", true, []string{"v1.30.0", "v1.33.1", "v2.0.0"}, []string{"v1.29.9"}},
+		{"-1.26", true, []string{"v1.20.0", "v1.26.15"}, []string{"v1.27.0"}},
+		{"1.29-1.27", false, nil, nil},
+		{"-", false, nil, nil},
+		{"latest", false, nil, nil},
+	}
+	for _, test := range testdata {
+		r, err := ParseRange(test.versions)
+		if (err == nil) != test.valid {
+			t.Errorf("ParseRange() failed, versions: %s, expected valid: %t, got error: %v", test.versions, test.valid, err)
+			continue
+		}
+		for _, v := range test.contains {
+			if !r.Contains(utilversion.MustParseGeneric(v)) {
+				t.Errorf("Range.Contains() failed, versions: %s, expected to contain: %s", test.versions, v)
+			}
+		}
+		for _, v := range test.excludes {
+			if r.Contains(utilversion.MustParseGeneric(v)) {
+				t.Errorf("Range.Contains() failed, versions: %s, expected not to contain: %s", test.versions, v)
+			}
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	binaries := []Binary{
+		{Path: "/usr/local/bin/kubectl", Version: "v1.33.1"},
+		{Path: "/home/u/bin/kubectl-1.29", Version: "v1.29.3"},
+		{Path: "/home/u/bin/kubectl-1.29.15", Version: "v1.29.15"},
+		{Path: "/home/u/bin/kubectl-1.27", Version: "v1.27.16"},
+	}
+	rules := []Rule{
+		{Versions: "-1.25", Path: "/opt/kubectl-legacy"},
+		{Versions: "1.31-1.32", Path: "/opt/kubectl-1.32"},
+	}
+
+	testdata := []struct {
+		contextPath string
+		server      string
+		expected    string
+		discovered  bool
+	}{
+		{"/opt/kubectl-pinned", "v1.29.1", "/opt/kubectl-pinned", false},
+		{"/opt/kubectl-pinned", "", "/opt/kubectl-pinned", false},
+		{"", "v1.24.17", "/opt/kubectl-legacy", false},
+		{"", "v1.31.4-gke.100", "/opt/kubectl-1.32", false},
+		{"", "v1.29.6-eks-a737599", "/home/u/bin/kubectl-1.29.15", true},
+		{"", "v1.28.2", "/home/u/bin/kubectl-1.29.15", true},
+		{"", "v1.26.9", "/home/u/bin/kubectl-1.27", true},
+		{"", "v1.34.0", "/usr/local/bin/kubectl", true},
+		{"", "v1.30.0", "/home/u/bin/kubectl-1.29.15", true},
+		{"", "v1.36.0", "", true},
+		{"", "", "", false},
+	}
+	for _, test := range testdata {
+		// 只在没有匹配的设置时查找已安装的 kubectl
+		var discovered bool
+		discover := func() []Binary {
+			discovered = true
+			return binaries
+		}
+
+		var got string
+		if resolution := Resolve(test.contextPath, rules, test.server, discover); resolution != nil {
+			got = resolution.Path
+		}
+		if got != test.expected {
+			t.Errorf("Resolve() failed, context path: %q, server: %q, expected: %q, got: %q", test.contextPath, test.server, test.expected, got)
+		}
+		if discovered != test.discovered {
+			t.Errorf("Resolve() failed, context path: %q, server: %q, expected discovered: %t, got: %t", test.contextPath, test.server, test.discovered, discovered)
+		}
+	}
+}
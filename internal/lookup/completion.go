@@ -0,0 +1,177 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lookup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"time"
+
+	"github.com/ketches/ktx/internal/cache"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/state"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// CompletionTTL is how long a cached list is used for completion before
+	// it is refreshed in the background.
+	CompletionTTL = 5 * time.Minute
+	// CompletionBudget is the time a live lookup may take in completion when
+	// nothing is cached.
+	CompletionBudget = time.Second
+	// RefreshTimeout is the timeout of a background refresh.
+	RefreshTimeout = 30 * time.Second
+	// RefreshCommand is the hidden ktx command refreshing a cached list.
+	RefreshCommand = "__refresh-cache"
+)
+
+// startRefresh refreshes the cached list in a background ktx process, which
+// keeps running after the completion exits.
+var startRefresh = func(kubeConfigFile, kind, contextName, namespace string) {
+	exe, err := os.Executable()
+	if err != nil {
+		return
+	}
+	// 不继承标准输入输出，避免阻塞或污染补全输出
+	c := exec.Command(exe, "--kubeconfig", kubeConfigFile, RefreshCommand, kind, contextName, namespace)
+	if c.Start() == nil {
+		c.Process.Release()
+	}
+}
+
+// CompleteNamespaces returns the namespaces of the context, the current
+// context if empty, for shell completion. See complete.
+func CompleteNamespaces(kubeConfigFile, contextName string) []string {
+	config, err := clientcmd.LoadFromFile(kubeConfigFile)
+	if err != nil {
+		return nil
+	}
+	if len(contextName) == 0 {
+		contextName = config.CurrentContext
+	}
+
+	items := complete(kubeConfigFile, cache.KindNamespaces, contextName, "")
+	if len(items) > 0 {
+		return items
+	}
+
+	// 没有缓存且无法及时列出时，使用已知的命名空间
+	if s, err := state.Load(state.DefaultFile); err == nil {
+		if meta, ok := s.Contexts[contextName]; ok {
+			items = append(items, meta.Namespaces...)
+		}
+	}
+	if ctx, ok := config.Contexts[contextName]; ok && len(ctx.Namespace) > 0 {
+		items = append(items, ctx.Namespace)
+	}
+	sort.Strings(items)
+	return items
+}
+
+// CompleteServiceAccounts returns the service accounts in the namespace of
+// the context, the current context and its namespace if empty, for shell
+// completion. See complete.
+func CompleteServiceAccounts(kubeConfigFile, contextName, namespace string) []string {
+	config, err := clientcmd.LoadFromFile(kubeConfigFile)
+	if err != nil {
+		return nil
+	}
+	if len(contextName) == 0 {
+		contextName = config.CurrentContext
+	}
+	if len(namespace) == 0 {
+		namespace = kube.DefaultNamespace
+		if ctx, ok := config.Contexts[contextName]; ok && len(ctx.Namespace) > 0 {
+			namespace = ctx.Namespace
+		}
+	}
+
+	return complete(kubeConfigFile, cache.KindServiceAccounts, contextName, namespace)
+}
+
+// Refresh lists and caches the names, run by the background ktx process.
+func Refresh(ctx context.Context, kubeConfigFile, kind, contextName, namespace string) error {
+	items, err := list(ctx, kubeConfigFile, kind, contextName, namespace, RefreshTimeout)
+	if err != nil {
+		return err
+	}
+	return cache.Put(kind, cacheKey(contextName, namespace), items)
+}
+
+// complete never blocks the completion longer than the budget: the cached
+// list is returned if cached, and refreshed in the background if older than
+// the ttl. Otherwise it is listed within the budget, and refreshed in the
+// background if it takes longer. Errors are ignored.
+func complete(kubeConfigFile, kind, contextName, namespace string) []string {
+	key := cacheKey(contextName, namespace)
+	if entry, _ := cache.Get(kind, key); entry != nil {
+		if !entry.Fresh(CompletionTTL) && cache.Claim(kind, key, RefreshTimeout) {
+			startRefresh(kubeConfigFile, kind, contextName, namespace)
+		}
+		return entry.Items
+	}
+
+	result := make(chan []string, 1)
+	go func() {
+		items, err := list(context.Background(), kubeConfigFile, kind, contextName, namespace, CompletionBudget)
+		if err == nil {
+			cache.Put(kind, key, items)
+		}
+		result <- items
+	}()
+
+	select {
+	case items := <-result:
+		return items
+	case <-time.After(CompletionBudget):
+		if cache.Claim(kind, key, RefreshTimeout) {
+			startRefresh(kubeConfigFile, kind, contextName, namespace)
+		}
+		return nil
+	}
+}
+
+func list(ctx context.Context, kubeConfigFile, kind, contextName, namespace string, timeout time.Duration) ([]string, error) {
+	var items []string
+	var err error
+	switch kind {
+	case cache.KindNamespaces:
+		items, err = kube.ListContextNamespaces(ctx, kubeConfigFile, contextName, timeout)
+	case cache.KindServiceAccounts:
+		items, err = kube.ListContextServiceAccounts(ctx, kubeConfigFile, contextName, namespace, timeout)
+	default:
+		return nil, fmt.Errorf("unknown cache kind %s", kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(items)
+	return items, nil
+}
+
+// cacheKey returns the cache key of the list, the namespaces are cached by
+// context, and namespaced resources by context and namespace.
+func cacheKey(contextName, namespace string) string {
+	if len(namespace) == 0 {
+		return contextName
+	}
+	return contextName + "/" + namespace
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lookup
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ketches/ktx/internal/cache"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/state"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestComplete(t *testing.T) {
+	dir := t.TempDir()
+	cache.Dir = filepath.Join(dir, "cache")
+	state.DefaultFile = filepath.Join(dir, "state.yaml")
+
+	var refreshed []string
+	startRefresh = func(kubeConfigFile, kind, contextName, namespace string) {
+		refreshed = append(refreshed, kind+" "+cacheKey(contextName, namespace))
+	}
+
+	fast := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/namespaces":
+			fmt.Fprint(w, `{"kind":"NamespaceList","apiVersion":"v1","metadata":{},"items":[{"metadata":{"name":"live"}}]}`)
+		case "/api/v1/namespaces/apps/serviceaccounts":
+			fmt.Fprint(w, `{"kind":"ServiceAccountList","apiVersion":"v1","metadata":{},"items":[{"metadata":{"name":"deployer"}},{"metadata":{"name":"default"}}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer fast.Close()
+	slow := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer slow.Close()
+
+	config := kube.NewConfig()
+	config.AuthInfos["user"] = &clientcmdapi.AuthInfo{Token: "opaque"}
+	for name, server := range map[string]*httptest.Server{"fast": fast, "slow": slow} {
+		config.Clusters[name] = &clientcmdapi.Cluster{Server: server.URL, InsecureSkipTLSVerify: true}
+		config.Contexts[name] = &clientcmdapi.Context{Cluster: name, AuthInfo: "user", Namespace: "apps"}
+	}
+	config.Contexts["cached"] = &clientcmdapi.Context{Cluster: "slow", AuthInfo: "user"}
+	config.Contexts["stale"] = &clientcmdapi.Context{Cluster: "slow", AuthInfo: "user"}
+	config.CurrentContext = "fast"
+	file := filepath.Join(dir, "config")
+	if err := clientcmd.WriteToFile(*config, file); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cache.Put(cache.KindNamespaces, "cached", []string{"a"}); err != nil {
+		t.Fatal(err)
+	}
+	stale := &cache.Entry{Kind: cache.KindNamespaces, Key: "stale", Items: []string{"b"}, Time: time.Now().Add(-2 * CompletionTTL)}
+	if err := cache.Save(stale); err != nil {
+		t.Fatal(err)
+	}
+
+	testdata := []struct {
+		name      string
+		complete  func() []string
+		expected  []string
+		refreshed []string
+	}{
+		{"fresh cache", func() []string { return CompleteNamespaces(file, "cached") }, []string{"a"}, nil},
+		{"stale cache", func() []string { return CompleteNamespaces(file, "stale") }, []string{"b"}, []string{"namespaces stale"}},
+		{"stale cache claimed", func() []string { return CompleteNamespaces(file, "stale") }, []string{"b"}, nil},
+		{"live", func() []string { return CompleteNamespaces(file, "") }, []string{"live"}, nil},
+		{"live cached", func() []string { return CompleteNamespaces(file, "fast") }, []string{"live"}, nil},
+		{"live service accounts", func() []string { return CompleteServiceAccounts(file, "", "") }, []string{"default", "deployer"}, nil},
+		{"over budget", func() []string { return CompleteNamespaces(file, "slow") }, []string{"apps"}, []string{"namespaces slow"}},
+	}
+	for _, test := range testdata {
+		refreshed = nil
+		start := time.Now()
+		got := test.complete()
+		if elapsed := time.Since(start); elapsed > CompletionBudget+500*time.Millisecond {
+			t.Errorf("%s: complete took %s, expected within the budget", test.name, elapsed)
+		}
+		if !slices.Equal(got, test.expected) || !slices.Equal(refreshed, test.refreshed) {
+			t.Errorf("%s: complete failed, expected: %v refreshing %v, got: %v refreshing %v", test.name, test.expected, test.refreshed, got, refreshed)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lookup
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/ketches/ktx/internal/cache"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/state"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Sources of the namespaces when they can not be listed.
+const (
+	SourceCache      = "cache"
+	SourceState      = "ktx metadata"
+	SourceKubeconfig = "kubeconfig"
+	SourceDiscovery  = "access reviews and role bindings"
+)
+
+// NamespaceList is the namespaces of a context.
+type NamespaceList struct {
+	Items []string
+	// Err is why the namespaces could not be listed, the items are partial
+	// and found from the sources if set.
+	Err     error
+	Sources []string
+}
+
+// Namespaces lists the namespaces of the context, the current context if
+// empty. If the namespaces can not be listed, it falls back to the cached
+// namespaces, the namespaces known in ktx metadata, the namespace of the
+// context, and if listing is forbidden, the namespaces discovered from access
+// reviews and role bindings.
+func Namespaces(ctx context.Context, kubeConfigFile, contextName string, timeout time.Duration) *NamespaceList {
+	config, err := clientcmd.LoadFromFile(kubeConfigFile)
+	if err != nil {
+		return &NamespaceList{Err: err}
+	}
+	if len(contextName) == 0 {
+		contextName = config.CurrentContext
+	}
+
+	items, err := kube.ListContextNamespaces(ctx, kubeConfigFile, contextName, timeout)
+	if err == nil {
+		sort.Strings(items)
+		// 缓存失败不影响结果
+		cache.Put(cache.KindNamespaces, contextName, items)
+		return &NamespaceList{Items: items}
+	}
+
+	list := &NamespaceList{Err: err}
+	found := make(map[string]bool)
+	add := func(source string, names []string) {
+		added := false
+		for _, name := range names {
+			if len(name) > 0 && !found[name] {
+				found[name] = true
+				list.Items = append(list.Items, name)
+				added = true
+			}
+		}
+		if added {
+			list.Sources = append(list.Sources, source)
+		}
+	}
+
+	if entry, _ := cache.Get(cache.KindNamespaces, contextName); entry != nil {
+		add(SourceCache, entry.Items)
+	}
+	if s, err := state.Load(state.DefaultFile); err == nil {
+		if meta, ok := s.Contexts[contextName]; ok {
+			add(SourceState, meta.Namespaces)
+		}
+	}
+	var namespace string
+	if c, ok := config.Contexts[contextName]; ok {
+		namespace = c.Namespace
+		add(SourceKubeconfig, []string{namespace})
+	}
+	// 集群不可达时跳过，避免再次等待超时
+	if apierrors.IsForbidden(err) {
+		if discovered, err := kube.DiscoverNamespaces(ctx, kubeConfigFile, contextName, namespace, timeout); err == nil {
+			add(SourceDiscovery, discovered)
+		}
+	}
+
+	sort.Strings(list.Items)
+	return list
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lookup
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ketches/ktx/internal/cache"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/state"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestNamespaces(t *testing.T) {
+	dir := t.TempDir()
+	cache.Dir = filepath.Join(dir, "cache")
+	state.DefaultFile = filepath.Join(dir, "state.yaml")
+
+	allowed := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/namespaces" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"kind":"NamespaceList","apiVersion":"v1","metadata":{},"items":[{"metadata":{"name":"b"}},{"metadata":{"name":"a"}}]}`)
+	}))
+	defer allowed.Close()
+	forbidden := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/apis/authorization.k8s.io/v1/selfsubjectrulesreviews":
+			fmt.Fprint(w, `{"kind":"SelfSubjectRulesReview","apiVersion":"authorization.k8s.io/v1","spec":{},"status":{"incomplete":false,"resourceRules":[{"verbs":["get"],"apiGroups":[""],"resources":["namespaces"],"resourceNames":["granted"]}],"nonResourceRules":[]}}`)
+		case "/apis/authentication.k8s.io/v1/selfsubjectreviews", "/apis/authentication.k8s.io/v1beta1/selfsubjectreviews":
+			http.NotFound(w, r)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Forbidden","code":403,"message":"forbidden"}`)
+		}
+	}))
+	defer forbidden.Close()
+	down := httptest.NewTLSServer(http.NotFoundHandler())
+	down.Close()
+
+	config := kube.NewConfig()
+	config.AuthInfos["user"] = &clientcmdapi.AuthInfo{Token: "opaque"}
+	for name, server := range map[string]*httptest.Server{"allowed": allowed, "forbidden": forbidden, "down": down} {
+		config.Clusters[name] = &clientcmdapi.Cluster{Server: server.URL, InsecureSkipTLSVerify: true}
+		config.Contexts[name] = &clientcmdapi.Context{Cluster: name, AuthInfo: "user"}
+	}
+	config.Contexts["forbidden"].Namespace = "team"
+	config.CurrentContext = "allowed"
+	file := filepath.Join(dir, "config")
+	if err := clientcmd.WriteToFile(*config, file); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := state.Load(state.DefaultFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Context("forbidden").Namespaces = []string{"shared", "team"}
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Put(cache.KindNamespaces, "down", []string{"x", "y"}); err != nil {
+		t.Fatal(err)
+	}
+
+	testdata := []struct {
+		context string
+		items   []string
+		sources []string
+		partial bool
+	}{
+		{"", []string{"a", "b"}, nil, false},
+		{"forbidden", []string{"granted", "shared", "team"}, []string{SourceState, SourceDiscovery}, true},
+		{"down", []string{"x", "y"}, []string{SourceCache}, true},
+	}
+	for _, test := range testdata {
+		got := Namespaces(context.Background(), file, test.context, time.Second)
+		if !slices.Equal(got.Items, test.items) || !slices.Equal(got.Sources, test.sources) || (got.Err != nil) != test.partial {
+			t.Errorf("Namespaces() failed, context: %s, expected: %v from %v, got: %v from %v, error: %v", test.context, test.items, test.sources, got.Items, got.Sources, got.Err)
+		}
+	}
+
+	if entry, err := cache.Get(cache.KindNamespaces, "allowed"); err != nil || entry == nil || !slices.Equal(entry.Items, []string{"a", "b"}) {
+		t.Errorf("Namespaces() failed, expected namespaces of the current context cached, got: %v, error: %v", entry, err)
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lookup
+
+import (
+	"context"
+	"time"
+
+	"github.com/ketches/ktx/internal/cache"
+	"github.com/ketches/ktx/internal/kube"
+)
+
+// ServerVersionTTL is how long a cached server version is used before it is
+// requested again, servers are rarely upgraded.
+const ServerVersionTTL = time.Hour
+
+// ServerVersionFailureTTL is how long a failed request for the server version
+// is cached, so that commands against an unreachable server do not wait for
+// the timeout every time.
+const ServerVersionFailureTTL = time.Minute
+
+// failedMark marks a cached server version as kept after a failed request.
+const failedMark = "failed"
+
+// probe and serverURL are replaced in tests.
+var (
+	probe     = kube.Probe
+	serverURL = kube.ServerURL
+)
+
+// ServerVersion returns the server version of the context, cached by the
+// server URL within ServerVersionTTL. If the server can not be reached, the
+// stale cached version is returned, or "" if never cached, and the server is
+// not requested again within ServerVersionFailureTTL.
+func ServerVersion(ctx context.Context, kubeConfigFile, contextName string, timeout time.Duration) string {
+	// 以服务端地址为键，不同 kubeconfig 中的同名 context（如 default）不共享缓存
+	key := kubeConfigFile + "#" + contextName
+	if server, err := serverURL(kubeConfigFile, contextName); err == nil {
+		key = server
+	}
+
+	entry, _ := cache.Get(cache.KindServerVersion, key)
+	var cached string
+	if entry != nil && len(entry.Items) > 0 {
+		cached = entry.Items[0]
+	}
+	switch {
+	case entry == nil:
+	case len(entry.Items) == 1 && entry.Fresh(ServerVersionTTL):
+		return cached
+	case len(entry.Items) == 2 && entry.Items[1] == failedMark && entry.Fresh(ServerVersionFailureTTL):
+		return cached
+	}
+
+	result := probe(ctx, kubeConfigFile, contextName, timeout)
+	if result.Err != nil {
+		_ = cache.Put(cache.KindServerVersion, key, []string{cached, failedMark})
+		return cached
+	}
+	_ = cache.Put(cache.KindServerVersion, key, []string{result.Version})
+	return result.Version
+}
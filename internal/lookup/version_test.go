@@ -0,0 +1,113 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lookup
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ketches/ktx/internal/cache"
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/types"
+)
+
+func TestServerVersion(t *testing.T) {
+	cache.Dir = filepath.Join(t.TempDir(), "cache")
+	defer func() { probe, serverURL = kube.Probe, kube.ServerURL }()
+	serverURL = func(kubeConfigFile, contextName string) (string, error) {
+		return "https://" + kubeConfigFile + contextName, nil
+	}
+
+	var (
+		probed  int
+		version = "v1.30.4"
+		err     error
+	)
+	probe = func(ctx context.Context, kubeConfigFile, contextName string, timeout time.Duration) *kube.ProbeResult {
+		probed++
+		if err != nil {
+			return &kube.ProbeResult{Status: types.ClusterStatusUnavailable, Err: err}
+		}
+		return &kube.ProbeResult{Status: types.ClusterStatusAvailable, Version: version}
+	}
+
+	if got := ServerVersion(context.Background(), "", "c1", time.Second); got != "v1.30.4" || probed != 1 {
+		t.Errorf("ServerVersion() failed, expected: v1.30.4 probed once, got: %s probed %d times", got, probed)
+	}
+
+	// 缓存未过期时不请求集群
+	version = "v1.31.0"
+	if got := ServerVersion(context.Background(), "", "c1", time.Second); got != "v1.30.4" || probed != 1 {
+		t.Errorf("ServerVersion() failed, expected cached: v1.30.4, got: %s probed %d times", got, probed)
+	}
+
+	// 缓存过期后重新请求
+	if err := cache.Save(&cache.Entry{Kind: cache.KindServerVersion, Key: "https://c1", Items: []string{"v1.30.4"}, Time: time.Now().Add(-2 * ServerVersionTTL)}); err != nil {
+		t.Fatal(err)
+	}
+	if got := ServerVersion(context.Background(), "", "c1", time.Second); got != "v1.31.0" || probed != 2 {
+		t.Errorf("ServerVersion() failed, expected refreshed: v1.31.0, got: %s probed %d times", got, probed)
+	}
+
+	// 集群不可用时使用过期的缓存，没有缓存则为空
+	if err := cache.Save(&cache.Entry{Kind: cache.KindServerVersion, Key: "https://c1", Items: []string{"v1.31.0"}, Time: time.Now().Add(-2 * ServerVersionTTL)}); err != nil {
+		t.Fatal(err)
+	}
+	err = errors.New("connection refused")
+	if got := ServerVersion(context.Background(), "", "c1", time.Second); got != "v1.31.0" {
+		t.Errorf("ServerVersion() failed, expected stale: v1.31.0, got: %s", got)
+	}
+	if got := ServerVersion(context.Background(), "", "c2", time.Second); got != "" {
+		t.Errorf("ServerVersion() failed, expected empty, got: %s", got)
+	}
+
+	// 失败的请求短暂缓存，期间不再请求集群
+	probed = 0
+	if got := ServerVersion(context.Background(), "", "c1", time.Second); got != "v1.31.0" || probed != 0 {
+		t.Errorf("ServerVersion() failed, expected stale: v1.31.0 not probed, got: %s probed %d times", got, probed)
+	}
+	if got := ServerVersion(context.Background(), "", "c2", time.Second); got != "" || probed != 0 {
+		t.Errorf("ServerVersion() failed, expected empty not probed, got: %s probed %d times", got, probed)
+	}
+
+	// 失败缓存过期后重新请求，保留过期的版本
+	if err := cache.Save(&cache.Entry{Kind: cache.KindServerVersion, Key: "https://c1", Items: []string{"v1.31.0", failedMark}, Time: time.Now().Add(-2 * ServerVersionFailureTTL)}); err != nil {
+		t.Fatal(err)
+	}
+	if got := ServerVersion(context.Background(), "", "c1", time.Second); got != "v1.31.0" || probed != 1 {
+		t.Errorf("ServerVersion() failed, expected stale: v1.31.0 probed once, got: %s probed %d times", got, probed)
+	}
+	err = nil
+	if err := cache.Save(&cache.Entry{Kind: cache.KindServerVersion, Key: "https://c2", Items: []string{"", failedMark}, Time: time.Now().Add(-2 * ServerVersionFailureTTL)}); err != nil {
+		t.Fatal(err)
+	}
+	if got := ServerVersion(context.Background(), "", "c2", time.Second); got != "v1.31.0" || probed != 2 {
+		t.Errorf("ServerVersion() failed, expected recovered: v1.31.0, got: %s probed %d times", got, probed)
+	}
+
+	// 不同 kubeconfig 中的同名 context 不共享缓存
+	if got := ServerVersion(context.Background(), "a/", "default", time.Second); got != "v1.31.0" || probed != 3 {
+		t.Errorf("ServerVersion() failed, expected: v1.31.0, got: %s probed %d times", got, probed)
+	}
+	version = "v1.29.2"
+	if got := ServerVersion(context.Background(), "b/", "default", time.Second); got != "v1.29.2" || probed != 4 {
+		t.Errorf("ServerVersion() failed, expected: v1.29.2 of another kubeconfig, got: %s probed %d times", got, probed)
+	}
+}
@@ -0,0 +1,406 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ketches/ktx/internal/util"
+)
+
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// ErrNoIDToken is returned when the token response contains no ID token, which
+// is optional when refreshing.
+var ErrNoIDToken = errors.New("token response contains no id_token")
+
+// loopbackHost is the host of the authorization code redirect, the redirect
+// URI to register at the issuer is http://127.0.0.1:<port>/callback.
+const loopbackHost = "127.0.0.1"
+
+// Client is an OpenID Connect client for a public or confidential client
+// registered at the issuer.
+type Client struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	// ListenPort is the loopback port receiving the authorization code
+	// redirect, a random port is used if zero.
+	ListenPort int
+	HTTPClient *http.Client
+
+	metadata *Metadata
+}
+
+// Metadata is the subset of the issuer discovery document used by ktx.
+type Metadata struct {
+	Issuer                      string `json:"issuer"`
+	AuthorizationEndpoint       string `json:"authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+}
+
+// Token is the token set returned by the issuer.
+type Token struct {
+	IDToken      string
+	RefreshToken string
+	// Expiry is the expiry of the ID token, zero if unknown.
+	Expiry time.Time
+}
+
+// DeviceAuthorization is the user facing part of a device authorization.
+type DeviceAuthorization struct {
+	UserCode                string
+	VerificationURI         string
+	VerificationURIComplete string
+}
+
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type deviceResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval"`
+}
+
+// Discover fetches the issuer discovery document.
+func (c *Client) Discover(ctx context.Context) (*Metadata, error) {
+	if c.metadata != nil {
+		return c.metadata, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.Issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("discovery of issuer %s failed: %s", c.Issuer, resp.Status)
+	}
+
+	var metadata Metadata
+	if err := json.NewDecoder(resp.Body).Decode(&metadata); err != nil {
+		return nil, fmt.Errorf("invalid discovery document of issuer %s: %w", c.Issuer, err)
+	}
+	c.metadata = &metadata
+	return c.metadata, nil
+}
+
+// AuthCodeLogin runs the authorization code flow with PKCE, the authorization
+// code is received on a loopback redirect. open is called with the URL the
+// user has to visit.
+func (c *Client) AuthCodeLogin(ctx context.Context, open func(authURL string)) (*Token, error) {
+	metadata, err := c.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 重定向地址与监听地址使用同一个回环 IP，localhost 可能优先解析为 ::1
+	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", loopbackHost, c.ListenPort))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for redirect: %w", err)
+	}
+	defer listener.Close()
+	redirectURI := fmt.Sprintf("http://%s:%d/callback", loopbackHost, listener.Addr().(*net.TCPAddr).Port)
+
+	state, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+	challenge := sha256.Sum256([]byte(verifier))
+
+	authURL, err := url.Parse(metadata.AuthorizationEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	q := authURL.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", c.ClientID)
+	q.Set("redirect_uri", redirectURI)
+	q.Set("scope", strings.Join(c.scopes(), " "))
+	q.Set("state", state)
+	q.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	q.Set("code_challenge_method", "S256")
+	authURL.RawQuery = q.Encode()
+
+	type result struct {
+		code string
+		err  error
+	}
+	results := make(chan result, 1)
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/callback" {
+				http.NotFound(w, r)
+				return
+			}
+
+			var res result
+			switch {
+			case r.URL.Query().Get("state") != state:
+				res.err = errors.New("state mismatch in authorization response")
+			case len(r.URL.Query().Get("error")) > 0:
+				res.err = fmt.Errorf("authorization failed: %s %s", r.URL.Query().Get("error"), r.URL.Query().Get("error_description"))
+			default:
+				res.code = r.URL.Query().Get("code")
+			}
+
+			if res.err != nil {
+				http.Error(w, res.err.Error(), http.StatusBadRequest)
+			} else {
+				fmt.Fprintln(w, "Login succeeded, you can close this window.")
+			}
+			select {
+			case results <- res:
+			default:
+			}
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go server.Serve(listener)
+	defer server.Close()
+
+	open(authURL.String())
+
+	var res result
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res = <-results:
+	}
+	if res.err != nil {
+		return nil, res.err
+	}
+
+	return c.token(ctx, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {res.code},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {verifier},
+	})
+}
+
+// DeviceLogin runs the device authorization flow, notify is called with the
+// code the user has to enter at the verification URI.
+func (c *Client) DeviceLogin(ctx context.Context, notify func(*DeviceAuthorization)) (*Token, error) {
+	metadata, err := c.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(metadata.DeviceAuthorizationEndpoint) == 0 {
+		return nil, fmt.Errorf("issuer %s does not support device authorization", c.Issuer)
+	}
+
+	form := url.Values{
+		"client_id": {c.ClientID},
+		"scope":     {strings.Join(c.scopes(), " ")},
+	}
+	if len(c.ClientSecret) > 0 {
+		form.Set("client_secret", c.ClientSecret)
+	}
+	var device deviceResponse
+	if err := c.post(ctx, metadata.DeviceAuthorizationEndpoint, form, &device); err != nil {
+		return nil, err
+	}
+	if len(device.DeviceCode) == 0 {
+		return nil, errors.New("device authorization returned no device code")
+	}
+
+	notify(&DeviceAuthorization{
+		UserCode:                device.UserCode,
+		VerificationURI:         device.VerificationURI,
+		VerificationURIComplete: device.VerificationURIComplete,
+	})
+
+	interval := time.Duration(util.If(device.Interval > 0, device.Interval, 5)) * time.Second
+	if device.ExpiresIn > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(device.ExpiresIn)*time.Second)
+		defer cancel()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("device authorization not completed: %w", ctx.Err())
+		case <-time.After(interval):
+		}
+
+		token, err := c.token(ctx, url.Values{
+			"grant_type":  {deviceCodeGrantType},
+			"device_code": {device.DeviceCode},
+		})
+		var tokenErr *TokenError
+		switch {
+		case errors.As(err, &tokenErr) && tokenErr.Code == "authorization_pending":
+			continue
+		case errors.As(err, &tokenErr) && tokenErr.Code == "slow_down":
+			interval += 5 * time.Second
+			continue
+		case err != nil:
+			return nil, err
+		}
+		return token, nil
+	}
+}
+
+// Refresh exchanges the refresh token for a new token set, the refresh token
+// is kept if the issuer does not rotate it. If the issuer returns no ID
+// token, ErrNoIDToken is returned with the token holding the refresh token,
+// which has to be saved since the previous one may be spent.
+func (c *Client) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	token, err := c.token(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+	if err != nil && !errors.Is(err, ErrNoIDToken) {
+		return nil, err
+	}
+	if len(token.RefreshToken) == 0 {
+		token.RefreshToken = refreshToken
+	}
+	return token, err
+}
+
+// TokenError is an OAuth 2.0 error returned by the token endpoint.
+type TokenError struct {
+	Code        string
+	Description string
+}
+
+func (e *TokenError) Error() string {
+	if len(e.Description) > 0 {
+		return fmt.Sprintf("token request failed: %s: %s", e.Code, e.Description)
+	}
+	return "token request failed: " + e.Code
+}
+
+func (c *Client) token(ctx context.Context, form url.Values) (*Token, error) {
+	metadata, err := c.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form.Set("client_id", c.ClientID)
+	if len(c.ClientSecret) > 0 {
+		form.Set("client_secret", c.ClientSecret)
+	}
+
+	var resp tokenResponse
+	if err := c.post(ctx, metadata.TokenEndpoint, form, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Error) > 0 {
+		return nil, &TokenError{Code: resp.Error, Description: resp.ErrorDescription}
+	}
+	if len(resp.IDToken) == 0 {
+		// 刷新令牌可能已被轮换，返回新的刷新令牌以便保存
+		return &Token{RefreshToken: resp.RefreshToken}, ErrNoIDToken
+	}
+
+	token := &Token{
+		IDToken:      resp.IDToken,
+		RefreshToken: resp.RefreshToken,
+	}
+	if expiry, ok := util.JWTExpiry(resp.IDToken); ok {
+		token.Expiry = expiry
+	} else if resp.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// post sends a form request and decodes the JSON response, OAuth error
+// responses are decoded as well since they are returned with status 400.
+func (c *Client) post(ctx context.Context, endpoint string, form url.Values, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusBadRequest && resp.StatusCode != http.StatusUnauthorized {
+		return fmt.Errorf("request to %s failed: %s", endpoint, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("invalid response from %s: %w", endpoint, err)
+	}
+	return nil
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+func (c *Client) scopes() []string {
+	if slices.Contains(c.Scopes, "openid") {
+		return c.Scopes
+	}
+	return append([]string{"openid"}, c.Scopes...)
+}
+
+// randomString returns a random URL safe string for the PKCE verifier and
+// the state.
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random string: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+// provider is a stand-in OpenID Connect provider issuing unsigned ID tokens.
+type provider struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	challenge   string
+	devicePolls int
+}
+
+func newProvider(t *testing.T) *provider {
+	p := &provider{}
+	mux := http.NewServeMux()
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Metadata{
+			Issuer:                      p.URL,
+			AuthorizationEndpoint:       p.URL + "/authorize",
+			TokenEndpoint:               p.URL + "/token",
+			DeviceAuthorizationEndpoint: p.URL + "/device",
+		})
+	})
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("code_challenge_method") != "S256" {
+			http.Error(w, "PKCE required", http.StatusBadRequest)
+			return
+		}
+		if redirect, err := url.Parse(q.Get("redirect_uri")); err != nil || redirect.Hostname() != loopbackHost {
+			http.Error(w, "redirect to the loopback IP required", http.StatusBadRequest)
+			return
+		}
+		p.mu.Lock()
+		p.challenge = q.Get("code_challenge")
+		p.mu.Unlock()
+		http.Redirect(w, r, q.Get("redirect_uri")+"?code=code1&state="+url.QueryEscape(q.Get("state")), http.StatusFound)
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(deviceResponse{
+			DeviceCode:      "device1",
+			UserCode:        "ABCD-EFGH",
+			VerificationURI: p.URL + "/activate",
+			ExpiresIn:       60,
+			Interval:        1,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		switch r.Form.Get("grant_type") {
+		case "authorization_code":
+			sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+			if r.Form.Get("code") != "code1" || base64.RawURLEncoding.EncodeToString(sum[:]) != p.challenge {
+				tokenError(w, "invalid_grant")
+				return
+			}
+			tokenResponseFor(w, "alice", "refresh1")
+		case deviceCodeGrantType:
+			p.devicePolls++
+			if p.devicePolls < 2 {
+				tokenError(w, "authorization_pending")
+				return
+			}
+			tokenResponseFor(w, "bob", "refresh2")
+		case "refresh_token":
+			switch r.Form.Get("refresh_token") {
+			case "refresh1":
+				tokenResponseFor(w, "alice", "")
+			case "refresh3":
+				// 不返回有效期
+				json.NewEncoder(w).Encode(tokenResponse{IDToken: idToken(`{"sub":"carol"}`)})
+			case "refresh4":
+				// 只轮换刷新令牌，不返回 ID 令牌
+				json.NewEncoder(w).Encode(tokenResponse{AccessToken: "access", RefreshToken: "refresh5"})
+			default:
+				tokenError(w, "invalid_grant")
+			}
+		default:
+			tokenError(w, "unsupported_grant_type")
+		}
+	})
+	return p
+}
+
+func tokenResponseFor(w http.ResponseWriter, sub, refreshToken string) {
+	payload := fmt.Sprintf(`{"sub":%q,"exp":%d}`, sub, time.Now().Add(time.Hour).Unix())
+	json.NewEncoder(w).Encode(tokenResponse{IDToken: idToken(payload), RefreshToken: refreshToken, ExpiresIn: 3600})
+}
+
+// idToken returns an unsigned ID token of the payload.
+func idToken(payload string) string {
+	return "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".sig"
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(tokenResponse{Error: code})
+}
+
+func TestAuthCodeLogin(t *testing.T) {
+	p := newProvider(t)
+	c := &Client{Issuer: p.URL, ClientID: "ktx"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	token, err := c.AuthCodeLogin(ctx, func(authURL string) {
+		// 模拟浏览器访问授权地址并跟随重定向
+		go http.Get(authURL)
+	})
+	if err != nil {
+		t.Fatalf("AuthCodeLogin() failed: %s", err)
+	}
+	if token.RefreshToken != "refresh1" {
+		t.Errorf("AuthCodeLogin() failed, expected refresh token: %s, got: %s", "refresh1", token.RefreshToken)
+	}
+	if time.Until(token.Expiry) < 59*time.Minute {
+		t.Errorf("AuthCodeLogin() failed, unexpected expiry: %s", token.Expiry)
+	}
+
+	refreshed, err := c.Refresh(ctx, token.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() failed: %s", err)
+	}
+	if refreshed.RefreshToken != "refresh1" {
+		t.Errorf("Refresh() failed, expected refresh token kept: %s, got: %s", "refresh1", refreshed.RefreshToken)
+	}
+
+	if _, err := c.Refresh(ctx, "unknown"); err == nil {
+		t.Errorf("Refresh() with invalid refresh token expected error")
+	}
+
+	// 没有有效期的令牌视为未知，而不是已过期
+	refreshed, err = c.Refresh(ctx, "refresh3")
+	if err != nil {
+		t.Fatalf("Refresh() failed: %s", err)
+	}
+	if !refreshed.Expiry.IsZero() {
+		t.Errorf("Refresh() failed, expected unknown expiry, got: %s", refreshed.Expiry)
+	}
+
+	// 没有 ID 令牌时返回轮换后的刷新令牌
+	refreshed, err = c.Refresh(ctx, "refresh4")
+	if !errors.Is(err, ErrNoIDToken) {
+		t.Fatalf("Refresh() failed, expected error: %s, got: %v", ErrNoIDToken, err)
+	}
+	if refreshed.RefreshToken != "refresh5" {
+		t.Errorf("Refresh() failed, expected rotated refresh token: %s, got: %s", "refresh5", refreshed.RefreshToken)
+	}
+}
+
+func TestDeviceLogin(t *testing.T) {
+	p := newProvider(t)
+	c := &Client{Issuer: p.URL, ClientID: "ktx"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var userCode string
+	token, err := c.DeviceLogin(ctx, func(da *DeviceAuthorization) {
+		userCode = da.UserCode
+	})
+	if err != nil {
+		t.Fatalf("DeviceLogin() failed: %s", err)
+	}
+	if userCode != "ABCD-EFGH" {
+		t.Errorf("DeviceLogin() failed, expected user code: %s, got: %s", "ABCD-EFGH", userCode)
+	}
+	if token.RefreshToken != "refresh2" {
+		t.Errorf("DeviceLogin() failed, expected refresh token: %s, got: %s", "refresh2", token.RefreshToken)
+	}
+}
@@ -33,6 +33,11 @@ func Note(format string, a ...interface{}) {
 	color.New(color.Faint).Printf("😼 "+format+"\n", a...)
 }
 
+// Warn prints a warning message.
+func Warn(format string, a ...interface{}) {
+	color.Yellow("🙀 "+format, a...)
+}
+
 // Done prints a done message.
 func Done(format string, a ...interface{}) {
 	color.Green("😺 "+format, a...)
@@ -42,3 +47,9 @@ func Done(format string, a ...interface{}) {
 func Fail(format string, a ...interface{}) {
 	color.Red("😾 "+format, a...)
 }
+
+// UseStderr redirects all messages to stderr, used when stdout is reserved
+// for machine-readable output, eg. exec credential plugins.
+func UseStderr() {
+	color.Output = color.Error
+}
@@ -19,10 +19,13 @@ package prompt
 import (
 	"fmt"
 	"os"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/ketches/ktx/internal/kube"
 	"github.com/ketches/ktx/internal/output"
+	"github.com/ketches/ktx/internal/state"
 	"github.com/ketches/ktx/internal/types"
 	"github.com/manifoldco/promptui"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
@@ -76,9 +79,37 @@ func TextInput(label, def string) string {
 	return result
 }
 
+// Password prompts the user to input a secret value, the prompt is written to
+// stderr so that it does not mix with machine-readable output on stdout.
+func Password(label string) string {
+	prompt := promptui.Prompt{
+		Label: promptui.Styler(promptui.FGYellow)(label),
+		Validate: func(input string) error {
+			if len(input) == 0 {
+				return fmt.Errorf("please input a valid value")
+			}
+			return nil
+		},
+		Mask: '*',
+		Templates: &promptui.PromptTemplates{
+			Prompt:          promptui.Styler(promptui.FGCyan)("➤ {{ . }} "),
+			ValidationError: promptui.Styler(promptui.FGRed)("✗ {{ . }}"),
+		},
+		HideEntered: true,
+		Stdout:      os.Stderr,
+	}
+	result, err := prompt.Run()
+	if err != nil {
+		output.Fatal("Prompt failed %v", err)
+	}
+
+	return result
+}
+
 // ContextSelection prompts the user to select a context
 func ContextSelection(label string, config *clientcmdapi.Config) string {
 	ctxs := kube.ListContexts(config)
+	sortByRecent(ctxs)
 	ctxs = append(ctxs, &types.ContextProfile{
 		Name:  "Exit",
 		Emoji: "✗",
@@ -99,7 +130,8 @@ func ContextSelection(label string, config *clientcmdapi.Config) string {
 {{ "Name:" | faint }}	{{ .Name }}
 {{ "Namespace:" | faint }}	{{ .Namespace }}
 {{ "Cluster:" | faint }}	{{ .Cluster }}
-{{ "User:" | faint }}	{{ .User }}
+{{ "User:" | faint }}	{{ .User }}{{if .Impersonate}}
+{{ "Impersonate:" | faint }}	{{ .Impersonate }}{{end}}
 {{ "Server:" | faint }}	{{ .Server }}{{end}}`,
 	}
 
@@ -135,3 +167,69 @@ func ContextSelection(label string, config *clientcmdapi.Config) string {
 
 	return ctxs[index].Name
 }
+
+// NamespaceSelection selects a namespace from the namespaces, with the
+// current one preselected. It exits if Exit is selected.
+func NamespaceSelection(label string, namespaces []string, current string) string {
+	namespace, ok := Selection(label, namespaces, current)
+	if !ok {
+		os.Exit(0)
+	}
+	return namespace
+}
+
+// Selection selects one of the items, with the current one preselected. It
+// returns false if Exit is selected, leaving the exit code to the caller.
+func Selection(label string, items []string, current string) (string, bool) {
+	cursorPos := max(slices.Index(items, current), 0)
+	items = append(slices.Clone(items), "Exit")
+
+	templates := &promptui.SelectTemplates{
+		Label:    promptui.Styler(promptui.FGYellow)("❖ {{ . }}:"),
+		Active:   promptui.Styler(promptui.FGCyan, promptui.FGUnderline)("➤ {{ . }}"),
+		Inactive: promptui.Styler(promptui.FGFaint)("  {{ . }}"),
+	}
+
+	prompt := promptui.Select{
+		Label: label,
+		Items: items,
+		Searcher: func(input string, index int) bool {
+			if index < 0 || index >= len(items)-1 {
+				return false
+			}
+			return strings.Contains(strings.ToLower(items[index]), strings.ToLower(input))
+		},
+		HideSelected: true,
+		CursorPos:    cursorPos,
+		Templates:    templates,
+		Size:         10,
+	}
+	index, _, err := prompt.Run()
+	if err != nil {
+		output.Fatal("Prompt failed %v", err)
+	}
+	if index == len(items)-1 {
+		return "", false
+	}
+	return items[index], true
+}
+
+// sortByRecent sorts the contexts by the switch history, most recently used
+// first, the contexts never switched to keep their order after them.
+func sortByRecent(ctxs []*types.ContextProfile) {
+	s, err := state.Load(state.DefaultFile)
+	if err != nil {
+		return
+	}
+	rank := make(map[string]int)
+	for i, name := range s.Recent() {
+		rank[name] = i + 1
+	}
+	sort.SliceStable(ctxs, func(i, j int) bool {
+		ri, rj := rank[ctxs[i].Name], rank[ctxs[j].Name]
+		if ri == 0 || rj == 0 {
+			return ri > rj
+		}
+		return ri < rj
+	})
+}
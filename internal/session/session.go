@@ -0,0 +1,194 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/ketches/ktx/internal/kube"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Env is the environment variable holding the session kubeconfig of a shell.
+const Env = "KTX_SESSION"
+
+// prevEnv is the environment variable holding the KUBECONFIG of a shell before
+// it entered the session, restored when leaving the session.
+const prevEnv = "KTX_PREV_KUBECONFIG"
+
+var Dir = filepath.Join(kube.DefaultStateDir, "sessions")
+
+// Shells supported by Script.
+var Shells = []string{"bash", "zsh", "fish", "powershell"}
+
+// Current returns the session kubeconfig of the current shell, empty if the
+// shell is not in a ktx session.
+func Current() string {
+	file := os.Getenv(Env)
+	if len(file) == 0 || filepath.Dir(file) != Dir {
+		return ""
+	}
+	if _, err := os.Stat(file); err != nil {
+		return ""
+	}
+	return file
+}
+
+// Create creates an empty session kubeconfig readable only by the current
+// user.
+func Create() (string, error) {
+	if err := os.MkdirAll(Dir, 0700); err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(Dir, fmt.Sprintf("%d-*.yaml", os.Getpid()))
+	if err != nil {
+		return "", err
+	}
+	return f.Name(), f.Close()
+}
+
+// Write writes the kubeconfig of the session.
+func Write(file string, config *clientcmdapi.Config) error {
+	return clientcmd.WriteToFile(*config, file)
+}
+
+// DetectShell returns the shell of the user from $SHELL, powershell on
+// Windows.
+func DetectShell() string {
+	if runtime.GOOS == "windows" {
+		return "powershell"
+	}
+	switch shell := filepath.Base(os.Getenv("SHELL")); shell {
+	case "zsh", "fish":
+		return shell
+	case "pwsh", "powershell":
+		return "powershell"
+	default:
+		return "bash"
+	}
+}
+
+// Script returns the shell script pointing KUBECONFIG at the session file,
+// and removing the file when the shell exits unless the cleanup is already
+// registered. Entering the session saves the previous KUBECONFIG, and the
+// previous EXIT trap in bash, which UnsetScript restores.
+func Script(shell, file string, cleanup bool) (string, error) {
+	q := quote(shell, file)
+	var b strings.Builder
+	switch shell {
+	case "bash":
+		if cleanup {
+			fmt.Fprintf(&b, "if [ -z \"${%s:-}\" ]; then\n", Env)
+			fmt.Fprintf(&b, "  [ -z \"${KUBECONFIG+x}\" ] || export %s=\"$KUBECONFIG\"\n", prevEnv)
+			// trap -p 输出 trap -- '<command>' EXIT，取第三个参数保存用户已有的 EXIT trap
+			b.WriteString("  __ktx_exit_trap() { __ktx_prev_exit_trap=${3:-}; }\n")
+			b.WriteString("  eval \"__ktx_exit_trap $(trap -p EXIT)\"\n")
+			b.WriteString("  unset -f __ktx_exit_trap\n")
+			b.WriteString("fi\n")
+		}
+		fmt.Fprintf(&b, "export KUBECONFIG=%s\n", q)
+		fmt.Fprintf(&b, "export %s=%s\n", Env, q)
+		if cleanup {
+			fmt.Fprintf(&b, "trap %s EXIT\n", quote(shell, "rm -f "+q+`; eval "${__ktx_prev_exit_trap:-}"`))
+		}
+	case "zsh":
+		if cleanup {
+			fmt.Fprintf(&b, "if [ -z \"${%s:-}\" ] && [ -n \"${KUBECONFIG+x}\" ]; then export %s=\"$KUBECONFIG\"; fi\n", Env, prevEnv)
+		}
+		fmt.Fprintf(&b, "export KUBECONFIG=%s\n", q)
+		fmt.Fprintf(&b, "export %s=%s\n", Env, q)
+		// zsh 在函数返回时执行函数内设置的 EXIT trap，ktx 包装函数中 eval
+		// 时会话文件会被立即删除，因此使用 zshexit 钩子
+		if cleanup {
+			fmt.Fprintf(&b, "__ktx_session_cleanup() { rm -f %s; }\n", q)
+			b.WriteString("autoload -Uz add-zsh-hook\n")
+			b.WriteString("add-zsh-hook zshexit __ktx_session_cleanup\n")
+		}
+	case "fish":
+		if cleanup {
+			fmt.Fprintf(&b, "if test -z \"$%s\"; and set -q KUBECONFIG; set -gx %s $KUBECONFIG; end\n", Env, prevEnv)
+		}
+		fmt.Fprintf(&b, "set -gx KUBECONFIG %s\n", q)
+		fmt.Fprintf(&b, "set -gx %s %s\n", Env, q)
+		if cleanup {
+			fmt.Fprintf(&b, "function __ktx_session_cleanup --on-event fish_exit; rm -f %s; end\n", q)
+		}
+	case "powershell":
+		if cleanup {
+			fmt.Fprintf(&b, "if (-not $env:%s -and $env:KUBECONFIG) { $env:%s = $env:KUBECONFIG }\n", Env, prevEnv)
+		}
+		fmt.Fprintf(&b, "$env:KUBECONFIG = %s\n", q)
+		fmt.Fprintf(&b, "$env:%s = %s\n", Env, q)
+		if cleanup {
+			fmt.Fprintf(&b, "$null = Register-EngineEvent PowerShell.Exiting -Action { Remove-Item -Force -ErrorAction SilentlyContinue %s }\n", q)
+		}
+	default:
+		return "", fmt.Errorf("unsupported shell %s, supported: %s", shell, strings.Join(Shells, ", "))
+	}
+	return b.String(), nil
+}
+
+// UnsetScript returns the shell script leaving the session, restoring the
+// KUBECONFIG saved by Script. Nothing is done outside a session.
+func UnsetScript(shell string) (string, error) {
+	var b strings.Builder
+	switch shell {
+	case "bash", "zsh":
+		fmt.Fprintf(&b, "if [ -n \"${%s:-}\" ]; then\n", Env)
+		fmt.Fprintf(&b, "  if [ -n \"${%[1]s+x}\" ]; then export KUBECONFIG=\"$%[1]s\"; else unset KUBECONFIG; fi\n", prevEnv)
+		fmt.Fprintf(&b, "  unset %s %s\n", prevEnv, Env)
+		if shell == "bash" {
+			b.WriteString("  if [ -n \"${__ktx_prev_exit_trap:-}\" ]; then trap -- \"$__ktx_prev_exit_trap\" EXIT; else trap - EXIT; fi\n")
+			b.WriteString("  unset __ktx_prev_exit_trap\n")
+		} else {
+			b.WriteString("  autoload -Uz add-zsh-hook\n")
+			b.WriteString("  add-zsh-hook -d zshexit __ktx_session_cleanup\n")
+			b.WriteString("  unfunction __ktx_session_cleanup 2>/dev/null\n")
+		}
+		b.WriteString("fi\n")
+	case "fish":
+		fmt.Fprintf(&b, "if set -q %s\n", Env)
+		fmt.Fprintf(&b, "    if set -q %[1]s; set -gx KUBECONFIG $%[1]s; else; set -e KUBECONFIG; end\n", prevEnv)
+		fmt.Fprintf(&b, "    set -e %s\n    set -e %s\n", prevEnv, Env)
+		b.WriteString("    functions -e __ktx_session_cleanup\nend\n")
+	case "powershell":
+		fmt.Fprintf(&b, "if ($env:%s) {\n", Env)
+		fmt.Fprintf(&b, "    if ($env:%[1]s) { $env:KUBECONFIG = $env:%[1]s } else { Remove-Item Env:KUBECONFIG -ErrorAction SilentlyContinue }\n", prevEnv)
+		fmt.Fprintf(&b, "    Remove-Item Env:%s -ErrorAction SilentlyContinue\n", prevEnv)
+		fmt.Fprintf(&b, "    Remove-Item Env:%s -ErrorAction SilentlyContinue\n}\n", Env)
+	default:
+		return "", fmt.Errorf("unsupported shell %s, supported: %s", shell, strings.Join(Shells, ", "))
+	}
+	return b.String(), nil
+}
+
+// quote quotes s as a single literal word of the shell.
+func quote(shell, s string) string {
+	switch shell {
+	case "fish":
+		return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+	case "powershell":
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	default:
+		return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScript(t *testing.T) {
+	file := "/home/o'neil/.kube/ktx/sessions/1.yaml"
+	testdata := []struct {
+		shell    string
+		cleanup  bool
+		expected []string
+	}{
+		{"bash", true, []string{`export KUBECONFIG='/home/o'\''neil/.kube/ktx/sessions/1.yaml'`, "export KTX_SESSION=", "trap "}},
+		{"zsh", false, []string{"export KUBECONFIG="}},
+		{"zsh", true, []string{`__ktx_session_cleanup() { rm -f '/home/o'\''neil/.kube/ktx/sessions/1.yaml'; }`, "add-zsh-hook zshexit __ktx_session_cleanup"}},
+		{"fish", true, []string{`set -gx KUBECONFIG '/home/o\'neil/.kube/ktx/sessions/1.yaml'`, "--on-event fish_exit"}},
+		{"powershell", true, []string{`$env:KUBECONFIG = '/home/o''neil/.kube/ktx/sessions/1.yaml'`, "PowerShell.Exiting"}},
+	}
+
+	for _, test := range testdata {
+		got, err := Script(test.shell, file, test.cleanup)
+		if err != nil {
+			t.Errorf("Script() failed, shell: %s, error: %s", test.shell, err)
+			continue
+		}
+		for _, expected := range test.expected {
+			if !strings.Contains(got, expected) {
+				t.Errorf("Script() failed, shell: %s, expected to contain: %s, got: %s", test.shell, expected, got)
+			}
+		}
+		if !test.cleanup && (strings.Contains(got, "trap") || strings.Contains(got, "zshexit")) {
+			t.Errorf("Script() failed, shell: %s, expected no cleanup, got: %s", test.shell, got)
+		}
+		// zsh 在函数返回时执行函数内设置的 EXIT trap
+		if test.shell == "zsh" && strings.Contains(got, "trap") {
+			t.Errorf("Script() failed, shell: zsh, expected no EXIT trap, got: %s", got)
+		}
+	}
+
+	if _, err := Script("tcsh", file, true); err == nil {
+		t.Errorf("Script() failed, expected error for unsupported shell")
+	}
+}
+
+func TestScriptBash(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not found")
+	}
+
+	file := filepath.Join(t.TempDir(), "1.yaml")
+	enter, _ := Script("bash", file, true)
+	leave, _ := UnsetScript("bash")
+
+	testdata := []struct {
+		name     string
+		script   string
+		expected string
+		removed  bool
+	}{
+		{
+			name:     "exit in the session",
+			script:   "trap 'echo user trap' EXIT\nexport KUBECONFIG=/prev\n" + enter + `echo "$KUBECONFIG"`,
+			expected: file + "\nuser trap\n",
+			removed:  true,
+		},
+		{
+			name:     "leave the session",
+			script:   "trap 'echo user trap' EXIT\nexport KUBECONFIG=/prev\n" + enter + leave + `echo "$KUBECONFIG ${KTX_SESSION:-none}"`,
+			expected: "/prev none\nuser trap\n",
+		},
+		{
+			name:     "leave the session without KUBECONFIG",
+			script:   "unset KUBECONFIG\n" + enter + leave + `echo "${KUBECONFIG-unset}"; trap -p EXIT`,
+			expected: "unset\n",
+		},
+	}
+
+	for _, test := range testdata {
+		if err := os.WriteFile(file, nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+		cmd := exec.Command(bash, "--norc", "--noprofile", "-c", test.script)
+		cmd.Env = []string{"PATH=" + os.Getenv("PATH")}
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Errorf("Script() failed, case: %s, error: %s, output: %s", test.name, err, out)
+			continue
+		}
+		if string(out) != test.expected {
+			t.Errorf("Script() failed, case: %s, expected: %q, got: %q", test.name, test.expected, out)
+		}
+		if _, err := os.Stat(file); os.IsNotExist(err) != test.removed {
+			t.Errorf("Script() failed, case: %s, expected removed: %t, got: %t", test.name, test.removed, os.IsNotExist(err))
+		}
+	}
+}
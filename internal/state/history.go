@@ -0,0 +1,92 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	"time"
+)
+
+// MaxHistory is the number of switches kept in the history.
+const MaxHistory = 100
+
+// Switch is a context and namespace switched to.
+type Switch struct {
+	Context   string    `json:"context"`
+	Namespace string    `json:"namespace,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// Record records a switch from a context and namespace to another. The origin
+// is recorded too if it is not the last entry, eg. switched by kubectl, so
+// that it can be returned to.
+func (s *State) Record(from, to Switch) {
+	if len(from.Context) > 0 && !s.isLast(from) {
+		s.History = append(s.History, &from)
+	}
+	if !s.isLast(to) {
+		s.History = append(s.History, &to)
+	} else {
+		s.History[len(s.History)-1].Time = to.Time
+	}
+	if len(s.History) > MaxHistory {
+		s.History = s.History[len(s.History)-MaxHistory:]
+	}
+}
+
+// Previous returns the most recent switch to a context or namespace other
+// than the current one, nil if there is none.
+func (s *State) Previous(context, namespace string) *Switch {
+	for i := len(s.History) - 1; i >= 0; i-- {
+		if sw := s.History[i]; sw.Context != context || sw.Namespace != namespace {
+			return sw
+		}
+	}
+	return nil
+}
+
+// PreviousNamespace returns the most recent namespace of the context other
+// than the current one, empty if there is none.
+func (s *State) PreviousNamespace(context, namespace string) string {
+	for i := len(s.History) - 1; i >= 0; i-- {
+		if sw := s.History[i]; sw.Context == context && sw.Namespace != namespace {
+			return sw.Namespace
+		}
+	}
+	return ""
+}
+
+// Recent returns the distinct contexts of the history, most recently used
+// first.
+func (s *State) Recent() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for i := len(s.History) - 1; i >= 0; i-- {
+		if name := s.History[i].Context; !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (s *State) isLast(sw Switch) bool {
+	if len(s.History) == 0 {
+		return false
+	}
+	last := s.History[len(s.History)-1]
+	return last.Context == sw.Context && last.Namespace == sw.Namespace
+}
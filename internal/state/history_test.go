@@ -0,0 +1,96 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "state.yaml")
+	s, err := Load(file)
+	if err != nil {
+		t.Fatalf("Load() failed, error: %s", err)
+	}
+
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	at := func(context, namespace string, minutes int) Switch {
+		return Switch{Context: context, Namespace: namespace, Time: now.Add(time.Duration(minutes) * time.Minute)}
+	}
+	s.Record(at("a", "default", 0), at("b", "default", 0))
+	s.Record(at("b", "default", 1), at("c", "apps", 1))
+	// 通过 kubectl 切换到 d 后再用 ktx 切换
+	s.Record(at("d", "default", 2), at("a", "default", 2))
+	s.Record(at("a", "default", 3), at("a", "default", 3))
+
+	if len(s.History) != 5 {
+		t.Fatalf("Record() failed, expected 5 entries, got %d", len(s.History))
+	}
+	if got := s.History[4].Time; !got.Equal(now.Add(3 * time.Minute)) {
+		t.Errorf("Record() failed, expected time of repeated switch updated, got %s", got)
+	}
+
+	testdata := []struct {
+		context   string
+		namespace string
+		expected  string
+	}{
+		{"a", "default", "d/default"},
+		{"a", "kube-system", "a/default"},
+		{"c", "apps", "a/default"},
+	}
+	for _, test := range testdata {
+		got := s.Previous(test.context, test.namespace)
+		if got == nil || got.Context+"/"+got.Namespace != test.expected {
+			t.Errorf("Previous() failed, current: %s/%s, expected: %s, got: %v", test.context, test.namespace, test.expected, got)
+		}
+	}
+
+	if got := s.PreviousNamespace("c", "default"); got != "apps" {
+		t.Errorf("PreviousNamespace() failed, expected: apps, got: %s", got)
+	}
+	if got := s.PreviousNamespace("c", "apps"); got != "" {
+		t.Errorf("PreviousNamespace() failed, expected none, got: %s", got)
+	}
+
+	if got, expected := s.Recent(), []string{"a", "d", "c", "b"}; !slices.Equal(got, expected) {
+		t.Errorf("Recent() failed, expected: %v, got: %v", expected, got)
+	}
+
+	s.Rename("a", "x")
+	s.Remove("d")
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() failed, error: %s", err)
+	}
+	s, err = Load(file)
+	if err != nil {
+		t.Fatalf("Load() failed, error: %s", err)
+	}
+	if got, expected := s.Recent(), []string{"x", "c", "b"}; !slices.Equal(got, expected) {
+		t.Errorf("Rename() and Remove() failed, expected: %v, got: %v", expected, got)
+	}
+
+	for i := 0; i < MaxHistory+10; i++ {
+		s.Record(Switch{}, at(string(rune('a'+i%2)), "", i))
+	}
+	if len(s.History) != MaxHistory {
+		t.Errorf("Record() failed, expected history trimmed to %d, got %d", MaxHistory, len(s.History))
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/kubectl"
+	"github.com/ketches/ktx/internal/util"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/yaml"
+)
+
+var DefaultFile = filepath.Join(kube.DefaultStateDir, "state.yaml")
+
+// State is the ktx metadata of contexts, kept out of the kubeconfig so that
+// kubectl and other tools are not affected.
+type State struct {
+	Contexts map[string]*Context `json:"contexts,omitempty"`
+	History  []*Switch           `json:"history,omitempty"`
+	// Kubectl are the kubectl binaries by server versions, the first matching
+	// rule is used.
+	Kubectl []kubectl.Rule `json:"kubectl,omitempty"`
+
+	file string
+}
+
+// Context is the ktx metadata of a context.
+type Context struct {
+	Labels map[string]string `json:"labels,omitempty"`
+	// Namespaces known in the context, listed when the namespaces can not be
+	// listed from the cluster.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Kubectl is the kubectl binary used for the context.
+	Kubectl string `json:"kubectl,omitempty"`
+}
+
+// Load loads the state from file, an empty state is returned if the file does
+// not exist.
+func Load(file string) (*State, error) {
+	s := &State{file: file}
+	data, err := os.ReadFile(file)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	if s.Contexts == nil {
+		s.Contexts = make(map[string]*Context)
+	}
+	return s, nil
+}
+
+// Save writes the state back to its file.
+func (s *State) Save() error {
+	for name, ctx := range s.Contexts {
+		if ctx.empty() {
+			delete(s.Contexts, name)
+		}
+	}
+
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return util.WriteFile(s.file, data)
+}
+
+// Context returns the metadata of the context, created if not exists.
+func (s *State) Context(name string) *Context {
+	ctx, ok := s.Contexts[name]
+	if !ok {
+		ctx = &Context{}
+		s.Contexts[name] = ctx
+	}
+	return ctx
+}
+
+// Rename moves the metadata of a renamed context.
+func (s *State) Rename(oldName, newName string) {
+	if ctx, ok := s.Contexts[oldName]; ok {
+		s.Contexts[newName] = ctx
+		delete(s.Contexts, oldName)
+	}
+	for _, sw := range s.History {
+		if sw.Context == oldName {
+			sw.Context = newName
+		}
+	}
+}
+
+// Remove removes the metadata of a removed context.
+func (s *State) Remove(name string) {
+	delete(s.Contexts, name)
+	s.History = slices.DeleteFunc(s.History, func(sw *Switch) bool {
+		return sw.Context == name
+	})
+}
+
+// SetKubectlRule sets the kubectl binary of the server versions, replacing
+// the rule of the same versions, or removes the rule if path is empty.
+func (s *State) SetKubectlRule(versions, path string) {
+	i := slices.IndexFunc(s.Kubectl, func(rule kubectl.Rule) bool {
+		return rule.Versions == versions
+	})
+	switch {
+	case len(path) == 0 && i >= 0:
+		s.Kubectl = slices.Delete(s.Kubectl, i, i+1)
+	case len(path) == 0:
+	case i >= 0:
+		s.Kubectl[i].Path = path
+	default:
+		s.Kubectl = append(s.Kubectl, kubectl.Rule{Versions: versions, Path: path})
+	}
+}
+
+// Select returns the sorted names of the contexts whose labels match the
+// selector.
+func (s *State) Select(names []string, selector labels.Selector) []string {
+	var selected []string
+	for _, name := range names {
+		var set labels.Set
+		if ctx, ok := s.Contexts[name]; ok {
+			set = ctx.Labels
+		}
+		if selector.Matches(set) {
+			selected = append(selected, name)
+		}
+	}
+	sort.Strings(selected)
+	return selected
+}
+
+func (c *Context) empty() bool {
+	return len(c.Labels) == 0 && len(c.Namespaces) == 0 && len(c.Kubectl) == 0
+}
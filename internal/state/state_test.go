@@ -0,0 +1,91 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ketches/ktx/internal/kubectl"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestState(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "state.yaml")
+
+	s, err := Load(file)
+	if err != nil {
+		t.Fatalf("Load() failed, error: %s", err)
+	}
+	s.Context("a").Labels = map[string]string{"env": "prod", "region": "eu"}
+	s.Context("b").Labels = map[string]string{"env": "prod"}
+	s.Context("c").Labels = map[string]string{"env": "dev"}
+	s.Context("empty")
+	s.Rename("c", "d")
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() failed, error: %s", err)
+	}
+
+	s, err = Load(file)
+	if err != nil {
+		t.Fatalf("Load() failed, error: %s", err)
+	}
+	if _, ok := s.Contexts["empty"]; ok {
+		t.Errorf("Save() failed, expected empty context metadata dropped")
+	}
+
+	testdata := []struct {
+		selector string
+		expected []string
+	}{
+		{"env=prod", []string{"a", "b"}},
+		{"env=prod,region=eu", []string{"a"}},
+		{"env in (dev)", []string{"d"}},
+		{"!region", []string{"b", "d", "x"}},
+	}
+	for _, test := range testdata {
+		selector, err := labels.Parse(test.selector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := s.Select([]string{"x", "d", "b", "a"}, selector); !slices.Equal(got, test.expected) {
+			t.Errorf("Select() failed, selector: %s, expected: %v, got: %v", test.selector, test.expected, got)
+		}
+	}
+}
+
+func TestSetKubectlRule(t *testing.T) {
+	s := &State{}
+	s.SetKubectlRule("1.27-1.29", "/bin/kubectl-1.29")
+	s.SetKubectlRule("-1.26", "/bin/kubectl-1.26")
+	s.SetKubectlRule("1.27-1.29", "/bin/kubectl-1.28")
+	s.SetKubectlRule("1.30", "")
+
+	expected := []kubectl.Rule{
+		{Versions: "1.27-1.29", Path: "/bin/kubectl-1.28"},
+		{Versions: "-1.26", Path: "/bin/kubectl-1.26"},
+	}
+	if !slices.Equal(s.Kubectl, expected) {
+		t.Errorf("SetKubectlRule() failed, expected: %v, got: %v", expected, s.Kubectl)
+	}
+
+	s.SetKubectlRule("1.27-1.29", "")
+	if expected = expected[1:]; !slices.Equal(s.Kubectl, expected) {
+		t.Errorf("SetKubectlRule() failed, expected: %v, got: %v", expected, s.Kubectl)
+	}
+}
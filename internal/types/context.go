@@ -16,7 +16,11 @@ limitations under the License.
 
 package types
 
-import "github.com/fatih/color"
+import (
+	"time"
+
+	"github.com/fatih/color"
+)
 
 // ContextProfile represents a context profile
 type ContextProfile struct {
@@ -27,25 +31,46 @@ type ContextProfile struct {
 	Server         string
 	Namespace      string
 	Emoji          string
+	Impersonate    string
 	ClusterStatus  ClusterStatus
 	ClusterVersion string
+	ClusterLatency time.Duration
+	ClusterMessage string
+	// Expiry is the earliest expiry of the context credentials, zero if
+	// unknown.
+	Expiry time.Time
 }
 
 type ClusterStatus string
 
 const (
-	ClusterStatusAvailable   ClusterStatus = "✓ Available"
-	ClusterStatusTimeout     ClusterStatus = "✗ Timeout"
-	ClusterStatusUnavailable ClusterStatus = "✗ Unavailable"
+	ClusterStatusAvailable          ClusterStatus = "✓ Available"
+	ClusterStatusTimeout            ClusterStatus = "✗ Timeout"
+	ClusterStatusUnavailable        ClusterStatus = "✗ Unavailable"
+	ClusterStatusDNSError           ClusterStatus = "✗ DNS Error"
+	ClusterStatusConnectionRefused  ClusterStatus = "✗ Connection Refused"
+	ClusterStatusTLSError           ClusterStatus = "✗ TLS Error"
+	ClusterStatusCertificateExpired ClusterStatus = "✗ Certificate Expired"
+	ClusterStatusUnauthenticated    ClusterStatus = "✗ Unauthenticated"
+	ClusterStatusForbidden          ClusterStatus = "✗ Forbidden"
+	ClusterStatusExecPluginMissing  ClusterStatus = "✗ Exec Plugin Missing"
+	ClusterStatusProxyError         ClusterStatus = "✗ Proxy Error"
+	ClusterStatusCanceled           ClusterStatus = "✗ Canceled"
+	ClusterStatusProbing            ClusterStatus = "… Probing"
 )
 
 func (cs ClusterStatus) ColorString() string {
 	switch cs {
 	case ClusterStatusAvailable:
 		return color.GreenString(string(cs))
-	case ClusterStatusUnavailable:
-		return color.RedString(string(cs))
-	default:
+	case ClusterStatusTimeout, ClusterStatusCanceled:
 		return string(cs)
+	case ClusterStatusProbing:
+		return color.New(color.Faint).Sprint(string(cs))
+	case ClusterStatusUnauthenticated, ClusterStatusForbidden:
+		// 集群可达，但凭据或权限有问题
+		return color.YellowString(string(cs))
+	default:
+		return color.RedString(string(cs))
 	}
 }
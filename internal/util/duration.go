@@ -0,0 +1,101 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+// durationPart matches a leading number and unit of a duration, units are
+// tried in order so that ms is not read as m.
+var durationPart = regexp.MustCompile(`^([0-9]*(?:\.[0-9]*)?)(ns|us|µs|μs|ms|s|m|h|d|w)`)
+
+// ParseDuration parses a duration like time.ParseDuration, and additionally
+// accepts days and weeks in any order, eg. 30d, 2w, 1d12h or 12h1d.
+func ParseDuration(s string) (time.Duration, error) {
+	input := s
+	sign := time.Duration(1)
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		if s[0] == '-' {
+			sign = -1
+		}
+		s = s[1:]
+	}
+	if s == "0" {
+		return 0, nil
+	}
+	if len(s) == 0 {
+		return 0, fmt.Errorf("invalid duration %q", input)
+	}
+
+	// 从左到右逐个解析数字和单位
+	var d time.Duration
+	for len(s) > 0 {
+		m := durationPart.FindStringSubmatch(s)
+		if m == nil || strings.Trim(m[1], ".") == "" {
+			return 0, fmt.Errorf("invalid duration %q", input)
+		}
+		switch m[2] {
+		case "d", "w":
+			n, err := strconv.ParseFloat(m[1], 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", input)
+			}
+			unit := day
+			if m[2] == "w" {
+				unit = 7 * day
+			}
+			d += time.Duration(n * float64(unit))
+		default:
+			part, err := time.ParseDuration(m[0])
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", input)
+			}
+			d += part
+		}
+		s = s[len(m[0]):]
+	}
+	return sign * d, nil
+}
+
+// HumanDuration formats the duration with its two most significant units,
+// eg. 45d, 3d4h, 2h30m, 42s.
+func HumanDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+
+	switch {
+	case d >= 10*day:
+		return fmt.Sprintf("%s%dd", sign, d/day)
+	case d >= day:
+		return fmt.Sprintf("%s%dd%dh", sign, d/day, d%day/time.Hour)
+	case d >= time.Hour:
+		return fmt.Sprintf("%s%dh%dm", sign, d/time.Hour, d%time.Hour/time.Minute)
+	case d >= time.Minute:
+		return fmt.Sprintf("%s%dm", sign, d/time.Minute)
+	default:
+		return fmt.Sprintf("%s%ds", sign, d/time.Second)
+	}
+}
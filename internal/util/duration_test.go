@@ -0,0 +1,74 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	testdata := []struct {
+		input    string
+		expected time.Duration
+		err      bool
+	}{
+		{"30d", 30 * 24 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"1w2d", 9 * 24 * time.Hour, false},
+		{"1d12h", 36 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"1d2w", 15 * 24 * time.Hour, false},
+		{"12h1d", 36 * time.Hour, false},
+		{"1h30m2d", 49*time.Hour + 30*time.Minute, false},
+		{"1w1d1h500ms", 8*24*time.Hour + time.Hour + 500*time.Millisecond, false},
+		{"1.5d", 36 * time.Hour, false},
+		{"-1d", -24 * time.Hour, false},
+		{"0", 0, false},
+		{"xd", 0, true},
+		{"1y", 0, true},
+		{"d", 0, true},
+		{"1d2", 0, true},
+		{"", 0, true},
+	}
+
+	for _, test := range testdata {
+		got, err := ParseDuration(test.input)
+		if (err != nil) != test.err || got != test.expected {
+			t.Errorf("ParseDuration() failed, input: %s, expected: %s (error: %t), got: %s (error: %v)", test.input, test.expected, test.err, got, err)
+		}
+	}
+}
+
+func TestHumanDuration(t *testing.T) {
+	testdata := []struct {
+		input    time.Duration
+		expected string
+	}{
+		{400 * 24 * time.Hour, "400d"},
+		{3*24*time.Hour + 4*time.Hour, "3d4h"},
+		{2*time.Hour + 30*time.Minute, "2h30m"},
+		{42 * time.Second, "42s"},
+		{-36 * time.Hour, "-1d12h"},
+	}
+
+	for _, test := range testdata {
+		if got := HumanDuration(test.input); got != test.expected {
+			t.Errorf("HumanDuration() failed, input: %s, expected: %s, got: %s", test.input, test.expected, got)
+		}
+	}
+}
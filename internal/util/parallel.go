@@ -0,0 +1,54 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"sync"
+)
+
+// Parallel calls fn for each item with at most n concurrent calls, items not
+// yet started when ctx is done are skipped. It returns once all started
+// calls have returned.
+func Parallel[T any](ctx context.Context, items []T, n int, fn func(ctx context.Context, item T)) {
+	if n <= 0 {
+		n = 1
+	}
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, n)
+	)
+	for _, item := range items {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(item T) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(ctx, item)
+		}(item)
+	}
+	wg.Wait()
+}
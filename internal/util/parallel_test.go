@@ -0,0 +1,60 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParallel(t *testing.T) {
+	items := make([]int, 20)
+
+	var running, peak, calls atomic.Int32
+	Parallel(context.Background(), items, 3, func(ctx context.Context, item int) {
+		calls.Add(1)
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+	})
+
+	if calls.Load() != 20 {
+		t.Errorf("Parallel() failed, expected calls: %d, got: %d", 20, calls.Load())
+	}
+	if peak.Load() > 3 {
+		t.Errorf("Parallel() failed, expected at most %d concurrent calls, got: %d", 3, peak.Load())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls.Store(0)
+	Parallel(ctx, items, 1, func(ctx context.Context, item int) {
+		if calls.Add(1) == 2 {
+			cancel()
+		}
+	})
+	if calls.Load() > 3 {
+		t.Errorf("Parallel() after cancel failed, expected at most %d calls, got: %d", 3, calls.Load())
+	}
+}
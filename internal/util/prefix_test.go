@@ -0,0 +1,43 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func TestPrefixWriter(t *testing.T) {
+	var (
+		buf bytes.Buffer
+		mu  sync.Mutex
+	)
+	a := NewPrefixWriter(&buf, "[a] ", &mu)
+	b := NewPrefixWriter(&buf, "[b] ", &mu)
+
+	a.Write([]byte("one\ntw"))
+	b.Write([]byte("three\n"))
+	a.Write([]byte("o\nfour"))
+	a.Flush()
+	b.Flush()
+
+	expected := "[a] one\n[b] three\n[a] two\n[a] four\n"
+	if buf.String() != expected {
+		t.Errorf("PrefixWriter failed, expected: %q, got: %q", expected, buf.String())
+	}
+}
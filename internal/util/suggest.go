@@ -0,0 +1,79 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxSuggestions is the number of suggestions returned by Suggest.
+const maxSuggestions = 3
+
+// Suggest returns the candidates similar to name, closest first: those
+// containing it, or within an edit distance of a third of its length.
+func Suggest(name string, candidates []string) []string {
+	type suggestion struct {
+		candidate string
+		distance  int
+	}
+
+	name = strings.ToLower(name)
+	maxDistance := max(len(name)/3, 1)
+	var suggestions []suggestion
+	for _, candidate := range candidates {
+		lower := strings.ToLower(candidate)
+		if lower == name {
+			continue
+		}
+		d := levenshtein(name, lower)
+		if d <= maxDistance || (len(name) > 1 && strings.Contains(lower, name)) {
+			suggestions = append(suggestions, suggestion{candidate, d})
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].distance < suggestions[j].distance
+	})
+
+	var result []string
+	for i := 0; i < len(suggestions) && i < maxSuggestions; i++ {
+		result = append(result, suggestions[i].candidate)
+	}
+	return result
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
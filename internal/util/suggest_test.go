@@ -0,0 +1,60 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	candidates := []string{"default", "kube-system", "kube-public", "payments", "payments-staging", "monitoring"}
+
+	testdata := []struct {
+		name     string
+		expected []string
+	}{
+		{"paymnets", []string{"payments"}},
+		{"payment", []string{"payments", "payments-staging"}},
+		{"kube", []string{"kube-system", "kube-public"}},
+		{"defualt", []string{"default"}},
+		{"Monitoring", nil},
+		{"istio-system", nil},
+	}
+	for _, test := range testdata {
+		if got := Suggest(test.name, candidates); !slices.Equal(got, test.expected) {
+			t.Errorf("Suggest() failed, name: %s, expected: %v, got: %v", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	testdata := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"default", "default", 0},
+		{"paymnets", "payments", 2},
+	}
+	for _, test := range testdata {
+		if got := levenshtein(test.a, test.b); got != test.expected {
+			t.Errorf("levenshtein() failed, a: %s, b: %s, expected: %d, got: %d", test.a, test.b, test.expected, got)
+		}
+	}
+}
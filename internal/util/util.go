@@ -18,6 +18,7 @@ package util
 
 import (
 	"os"
+	"path/filepath"
 )
 
 // IsFileExist checks if a file exists.
@@ -26,6 +27,38 @@ func IsFileExist(filename string) bool {
 	return !os.IsNotExist(err)
 }
 
+// WriteFile atomically writes data to file readable only by the current user,
+// creating parent directories as needed. The data is written to a unique
+// temporary file renamed over the file, so concurrent writers never
+// interleave and readers never see a partial file.
+func WriteFile(file string, data []byte) error {
+	dir := filepath.Dir(file)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, "."+filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	// 重命名成功后临时文件已不存在，删除失败可以忽略
+	defer os.Remove(tmp)
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
 // If returns then if cond is true, otherwise els.
 func If[T any](cond bool, then T, els T) T {
 	if cond {
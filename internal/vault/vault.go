@@ -0,0 +1,215 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ketches/ktx/internal/kube"
+	"github.com/ketches/ktx/internal/util"
+)
+
+const (
+	// PassphraseEnv is the environment variable read for the vault passphrase
+	// before prompting, so that non-interactive kubectl calls can unlock it.
+	PassphraseEnv = "KTX_VAULT_PASSPHRASE"
+
+	fileVersion = 1
+	iterations  = 600000
+	// minIterations is the lowest iteration count accepted from a vault
+	// file, a tampered file must not weaken the key derivation.
+	minIterations = 100000
+	keyLength     = 32
+	saltLength    = 16
+)
+
+var (
+	DefaultFile = filepath.Join(kube.DefaultStateDir, "vault")
+
+	// ErrWrongPassphrase is returned when the vault can not be decrypted.
+	ErrWrongPassphrase = errors.New("wrong passphrase or corrupted vault")
+)
+
+// Entry is a credential stored in the vault.
+type Entry struct {
+	Token                 string `json:"token,omitempty"`
+	ClientCertificateData []byte `json:"clientCertificateData,omitempty"`
+	ClientKeyData         []byte `json:"clientKeyData,omitempty"`
+	OIDC                  *OIDC  `json:"oidc,omitempty"`
+}
+
+// OIDC is an OpenID Connect login stored in the vault, the ID token is
+// refreshed with the refresh token when it expires.
+type OIDC struct {
+	Issuer       string    `json:"issuer"`
+	ClientID     string    `json:"clientID"`
+	ClientSecret string    `json:"clientSecret,omitempty"`
+	Scopes       []string  `json:"scopes,omitempty"`
+	IDToken      string    `json:"idToken,omitempty"`
+	RefreshToken string    `json:"refreshToken,omitempty"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+// Vault is a passphrase encrypted credential store keyed by context name.
+type Vault struct {
+	Entries map[string]*Entry
+
+	file string
+	salt []byte
+	key  []byte
+	// iterations derived the key, kept when saving so that vaults created with
+	// other iteration counts still open.
+	iterations int
+}
+
+// vaultFile is the on-disk representation of the vault.
+type vaultFile struct {
+	Version    int    `json:"version"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Data       []byte `json:"data"`
+}
+
+// Exist checks if the vault file exists.
+func Exist(file string) bool {
+	_, err := os.Stat(file)
+	return err == nil
+}
+
+// Open decrypts the vault file with the passphrase, an empty vault is
+// returned if the file does not exist yet.
+func Open(file string, passphrase []byte) (*Vault, error) {
+	v := &Vault{
+		Entries:    make(map[string]*Entry),
+		file:       file,
+		iterations: iterations,
+	}
+
+	raw, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		v.salt = make([]byte, saltLength)
+		if _, err := rand.Read(v.salt); err != nil {
+			return nil, err
+		}
+		v.key, err = deriveKey(passphrase, v.salt, v.iterations)
+		return v, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var vf vaultFile
+	if err := json.Unmarshal(raw, &vf); err != nil {
+		return nil, fmt.Errorf("invalid vault file %s: %w", file, err)
+	}
+	if vf.Version != fileVersion {
+		return nil, fmt.Errorf("unsupported vault version %d", vf.Version)
+	}
+	if vf.Iterations < minIterations {
+		return nil, fmt.Errorf("invalid vault file %s: iteration count %d is below %d", file, vf.Iterations, minIterations)
+	}
+
+	v.salt, v.iterations = vf.Salt, vf.Iterations
+	v.key, err = deriveKey(passphrase, vf.Salt, vf.Iterations)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := Decrypt(v.key, vf.Data)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	if err := json.Unmarshal(data, &v.Entries); err != nil {
+		return nil, fmt.Errorf("invalid vault content: %w", err)
+	}
+	return v, nil
+}
+
+// Save encrypts and writes the vault back to its file.
+func (v *Vault) Save() error {
+	data, err := json.Marshal(v.Entries)
+	if err != nil {
+		return err
+	}
+
+	sealed, err := Encrypt(v.key, data)
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(vaultFile{
+		Version:    fileVersion,
+		Iterations: v.iterations,
+		Salt:       v.salt,
+		Data:       sealed,
+	})
+	if err != nil {
+		return err
+	}
+
+	return util.WriteFile(v.file, raw)
+}
+
+// Encrypt seals plaintext with AES-GCM, the nonce is prepended to the result.
+func Encrypt(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Decrypt opens data sealed by Encrypt.
+func Decrypt(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func deriveKey(passphrase, salt []byte, iter int) ([]byte, error) {
+	return pbkdf2.Key(sha256.New, string(passphrase), salt, iter, keyLength)
+}
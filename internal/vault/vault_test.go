@@ -0,0 +1,113 @@
+/*
+Copyright 2025 The Ketches Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ketches/ktx/internal/util"
+)
+
+func TestVault(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "vault")
+
+	v, err := Open(file, []byte("secret"))
+	if err != nil {
+		t.Fatalf("Open() failed: %s", err)
+	}
+	v.Entries["context1"] = &Entry{Token: "token1"}
+	v.Entries["context2"] = &Entry{ClientCertificateData: []byte("cert"), ClientKeyData: []byte("key")}
+	if err := v.Save(); err != nil {
+		t.Fatalf("Save() failed: %s", err)
+	}
+
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("ReadFile() failed: %s", err)
+	}
+	if bytes.Contains(raw, []byte("token1")) {
+		t.Errorf("Save() failed, vault file contains plaintext token")
+	}
+
+	v, err = Open(file, []byte("secret"))
+	if err != nil {
+		t.Fatalf("Open() failed: %s", err)
+	}
+	if v.Entries["context1"].Token != "token1" {
+		t.Errorf("Open() failed, expected token: %s, got: %s", "token1", v.Entries["context1"].Token)
+	}
+	if string(v.Entries["context2"].ClientKeyData) != "key" {
+		t.Errorf("Open() failed, expected key: %s, got: %s", "key", v.Entries["context2"].ClientKeyData)
+	}
+
+	if _, err := Open(file, []byte("wrong")); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Open() with wrong passphrase, expected error: %s, got: %v", ErrWrongPassphrase, err)
+	}
+}
+
+func TestVaultIterations(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "vault")
+
+	// 以其他迭代次数创建的 vault 保存后仍然可以打开
+	salt := []byte("0123456789abcdef")
+	key, err := deriveKey([]byte("secret"), salt, 100000)
+	if err != nil {
+		t.Fatalf("deriveKey() failed: %s", err)
+	}
+	sealed, err := Encrypt(key, []byte("{}"))
+	if err != nil {
+		t.Fatalf("Encrypt() failed: %s", err)
+	}
+	raw, _ := json.Marshal(vaultFile{Version: fileVersion, Iterations: 100000, Salt: salt, Data: sealed})
+	if err := util.WriteFile(file, raw); err != nil {
+		t.Fatalf("WriteFile() failed: %s", err)
+	}
+
+	v, err := Open(file, []byte("secret"))
+	if err != nil {
+		t.Fatalf("Open() failed: %s", err)
+	}
+	v.Entries["context1"] = &Entry{Token: "token1"}
+	if err := v.Save(); err != nil {
+		t.Fatalf("Save() failed: %s", err)
+	}
+
+	raw, _ = os.ReadFile(file)
+	var vf vaultFile
+	if err := json.Unmarshal(raw, &vf); err != nil || vf.Iterations != 100000 {
+		t.Errorf("Save() failed, expected iterations: 100000, got: %d, error: %v", vf.Iterations, err)
+	}
+	if v, err = Open(file, []byte("secret")); err != nil || v.Entries["context1"].Token != "token1" {
+		t.Errorf("Open() failed after Save(), error: %v", err)
+	}
+
+	// 拒绝过低的迭代次数
+	for _, n := range []int{0, -1, 1000} {
+		raw, _ = json.Marshal(vaultFile{Version: fileVersion, Iterations: n, Salt: salt, Data: sealed})
+		if err := util.WriteFile(file, raw); err != nil {
+			t.Fatalf("WriteFile() failed: %s", err)
+		}
+		if _, err := Open(file, []byte("secret")); err == nil {
+			t.Errorf("Open() failed, expected error for iterations: %d", n)
+		}
+	}
+}